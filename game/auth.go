@@ -0,0 +1,74 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Default file the player token is kept in between runs.
+const defaultTokenPath = "token"
+
+// tokenPath returns the player token file set in TOKEN_PATH.
+func tokenPath() string {
+	if path := os.Getenv("TOKEN_PATH"); path != "" {
+		return path
+	}
+	return defaultTokenPath
+}
+
+// loadToken reads the saved player token, empty when there is none.
+func loadToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(data)), err
+}
+
+type loginRequest struct {
+	Token string `json:"token"`
+}
+
+type loginResponse struct {
+	PlayerID string `json:"playerID"`
+	Token    string `json:"token"`
+}
+
+// login asks the server for a player token. The previous token, expired or
+// not, keeps the same player.
+func login(url, gameToken, previous string) (string, error) {
+	body, err := json.Marshal(loginRequest{Token: previous})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", gameToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("login failed: %s", resp.Status)
+	}
+	var res loginResponse
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("can't parse login response: %w", err)
+	}
+	return res.Token, nil
+}
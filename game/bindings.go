@@ -0,0 +1,189 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+)
+
+// Default file the key bindings are loaded from and saved to.
+const defaultBindingsPath = "bindings.json"
+
+// action is something the player can do, whatever the input device.
+type action int
+
+const (
+	actionMoveLeft action = iota
+	actionMoveRight
+	actionMoveUp
+	actionMoveDown
+	actionAttack
+	actionChat
+	actionToggleDebug
+	actionBindingsMenu
+)
+
+// actions lists every action in the order of the rebinding screen.
+var actions = []action{
+	actionMoveLeft, actionMoveRight, actionMoveUp, actionMoveDown,
+	actionAttack, actionChat, actionToggleDebug, actionBindingsMenu,
+}
+
+var actionNames = map[action]string{
+	actionMoveLeft:     "move_left",
+	actionMoveRight:    "move_right",
+	actionMoveUp:       "move_up",
+	actionMoveDown:     "move_down",
+	actionAttack:       "attack",
+	actionChat:         "chat",
+	actionToggleDebug:  "toggle_debug",
+	actionBindingsMenu: "bindings_menu",
+}
+
+func (a action) String() string {
+	return actionNames[a]
+}
+
+var buttonNames = map[e.StandardGamepadButton]string{
+	e.StandardGamepadButtonRightBottom:      "right_bottom",
+	e.StandardGamepadButtonRightRight:       "right_right",
+	e.StandardGamepadButtonRightLeft:        "right_left",
+	e.StandardGamepadButtonRightTop:         "right_top",
+	e.StandardGamepadButtonFrontTopLeft:     "front_top_left",
+	e.StandardGamepadButtonFrontTopRight:    "front_top_right",
+	e.StandardGamepadButtonFrontBottomLeft:  "front_bottom_left",
+	e.StandardGamepadButtonFrontBottomRight: "front_bottom_right",
+	e.StandardGamepadButtonCenterLeft:       "center_left",
+	e.StandardGamepadButtonCenterRight:      "center_right",
+	e.StandardGamepadButtonLeftStick:        "left_stick",
+	e.StandardGamepadButtonRightStick:       "right_stick",
+	e.StandardGamepadButtonLeftTop:          "left_top",
+	e.StandardGamepadButtonLeftBottom:       "left_bottom",
+	e.StandardGamepadButtonLeftLeft:         "left_left",
+	e.StandardGamepadButtonLeftRight:        "left_right",
+	e.StandardGamepadButtonCenterCenter:     "center_center",
+}
+
+// bindings maps actions to keyboard keys and gamepad buttons.
+type bindings struct {
+	keys    map[action][]e.Key
+	buttons map[action][]e.StandardGamepadButton
+
+	// File the bindings are saved to.
+	path string
+}
+
+func defaultBindings() *bindings {
+	return &bindings{
+		keys: map[action][]e.Key{
+			actionMoveLeft:     {e.KeyA, e.KeyLeft},
+			actionMoveRight:    {e.KeyD, e.KeyRight},
+			actionMoveUp:       {e.KeyW, e.KeyUp},
+			actionMoveDown:     {e.KeyS, e.KeyDown},
+			actionAttack:       {e.KeySpace},
+			actionChat:         {e.KeyT},
+			actionToggleDebug:  {e.KeyF3},
+			actionBindingsMenu: {e.KeyF1},
+		},
+		buttons: map[action][]e.StandardGamepadButton{
+			actionMoveLeft:     {e.StandardGamepadButtonLeftLeft},
+			actionMoveRight:    {e.StandardGamepadButtonLeftRight},
+			actionMoveUp:       {e.StandardGamepadButtonLeftTop},
+			actionMoveDown:     {e.StandardGamepadButtonLeftBottom},
+			actionAttack:       {e.StandardGamepadButtonRightBottom},
+			actionChat:         {},
+			actionToggleDebug:  {e.StandardGamepadButtonCenterLeft},
+			actionBindingsMenu: {e.StandardGamepadButtonCenterRight},
+		},
+		path: defaultBindingsPath,
+	}
+}
+
+// bindKey makes the key the only one triggering the action.
+func (b *bindings) bindKey(a action, key e.Key) {
+	b.keys[a] = []e.Key{key}
+}
+
+// bindButton makes the gamepad button the only one triggering the action.
+func (b *bindings) bindButton(a action, button e.StandardGamepadButton) {
+	b.buttons[a] = []e.StandardGamepadButton{button}
+}
+
+// binding is how an action is stored in the bindings file, e.g.
+//
+//	"move_left": {"keys": ["A", "ArrowLeft"], "buttons": ["left_left"]}
+type binding struct {
+	Keys    []e.Key  `json:"keys"`
+	Buttons []string `json:"buttons"`
+}
+
+// loadBindings reads the bindings file. Actions missing from the file keep
+// their default bindings, and a missing file gives the defaults.
+func loadBindings(path string) (*bindings, error) {
+	b := defaultBindings()
+	b.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return b, err
+	}
+
+	var file map[string]binding
+	if err = json.Unmarshal(data, &file); err != nil {
+		return b, fmt.Errorf("can't parse bindings %s: %w", path, err)
+	}
+
+	for _, a := range actions {
+		bound, ok := file[a.String()]
+		if !ok {
+			continue
+		}
+		b.keys[a] = bound.Keys
+		b.buttons[a] = nil
+		for _, name := range bound.Buttons {
+			button, ok := buttonByName(name)
+			if !ok {
+				return b, fmt.Errorf("unknown gamepad button %q for %s", name, a)
+			}
+			b.buttons[a] = append(b.buttons[a], button)
+		}
+	}
+	return b, nil
+}
+
+// save writes the bindings to their file.
+func (b *bindings) save() error {
+	file := make(map[string]binding, len(actions))
+	for _, a := range actions {
+		bound := binding{Keys: b.keys[a], Buttons: []string{}}
+		if bound.Keys == nil {
+			bound.Keys = []e.Key{}
+		}
+		for _, button := range b.buttons[a] {
+			bound.Buttons = append(bound.Buttons, buttonNames[button])
+		}
+		file[a.String()] = bound
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.path, data, 0644)
+}
+
+func buttonByName(name string) (e.StandardGamepadButton, bool) {
+	for button, n := range buttonNames {
+		if n == name {
+			return button, true
+		}
+	}
+	return 0, false
+}
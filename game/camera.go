@@ -0,0 +1,93 @@
+package game
+
+import (
+	"math"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+	w "github.com/patrick-me/game_one/world"
+)
+
+const (
+	minZoom = 1.0
+	maxZoom = 4.0
+
+	// Share of the distance to the target the camera covers every tick.
+	followSmoothing = 0.15
+)
+
+// camera is the part of the world shown on the screen. It follows the local
+// player and never shows anything outside the world bounds.
+type camera struct {
+	// World position of the center of the view.
+	x, y float64
+	zoom float64
+
+	// Size of the screen in pixels.
+	width, height float64
+
+	// Whether the camera has been placed on its target yet.
+	placed bool
+}
+
+func newCamera(width, height int) *camera {
+	return &camera{
+		zoom:   minZoom,
+		width:  float64(width),
+		height: float64(height),
+	}
+}
+
+// follow moves the camera towards the target, jumping to it the first time.
+func (c *camera) follow(x, y float64, bounds w.Rect) {
+	if !c.placed {
+		c.x, c.y = x, y
+		c.placed = true
+	} else {
+		c.x += (x - c.x) * followSmoothing
+		c.y += (y - c.y) * followSmoothing
+	}
+	c.clamp(bounds)
+}
+
+// zoomBy multiplies the zoom, keeping it within limits.
+func (c *camera) zoomBy(factor float64, bounds w.Rect) {
+	c.zoom = math.Max(minZoom, math.Min(maxZoom, c.zoom*factor))
+	c.clamp(bounds)
+}
+
+// clamp keeps the view inside the bounds, centering it when the bounds are
+// smaller than the view.
+func (c *camera) clamp(bounds w.Rect) {
+	c.x = clampAxis(c.x, c.width/c.zoom, bounds.X, bounds.W)
+	c.y = clampAxis(c.y, c.height/c.zoom, bounds.Y, bounds.H)
+}
+
+func clampAxis(center, view, min, size float64) float64 {
+	if view >= size {
+		return min + size/2
+	}
+	return math.Max(min+view/2, math.Min(min+size-view/2, center))
+}
+
+// geoM returns the transformation from world to screen coordinates.
+func (c *camera) geoM() e.GeoM {
+	var m e.GeoM
+	m.Translate(-c.x, -c.y)
+	m.Scale(c.zoom, c.zoom)
+	m.Translate(c.width/2, c.height/2)
+	return m
+}
+
+// worldToScreen converts a world position to screen pixels.
+func (c *camera) worldToScreen(x, y float64) (float64, float64) {
+	m := c.geoM()
+	return m.Apply(x, y)
+}
+
+// screenToWorld converts screen pixels, e.g. a cursor position, to a world
+// position.
+func (c *camera) screenToWorld(x, y float64) (float64, float64) {
+	m := c.geoM()
+	m.Invert()
+	return m.Apply(x, y)
+}
@@ -0,0 +1,167 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sync"
+	"time"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	events "github.com/patrick-me/game_one/proto"
+	w "github.com/patrick-me/game_one/world"
+)
+
+// How long the attack animation lasts.
+const attackAnimation = 250 * time.Millisecond
+
+var (
+	swingColor   = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xe0}
+	hpBackground = color.RGBA{R: 0x80, A: 0xff}
+	hpColor      = color.RGBA{R: 0x40, G: 0xd0, B: 0x40, A: 0xff}
+)
+
+// attacks tracks the attack animations of the units. The connection reader
+// starts those of the remote units and the game loop the local one's.
+type attacks struct {
+	mu      sync.Mutex
+	started map[string]time.Time
+}
+
+func newAttacks() *attacks {
+	return &attacks{started: make(map[string]time.Time)}
+}
+
+func (a *attacks) start(id string, at time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.started[id] = at
+}
+
+// progress returns how far the attack animation of the unit is, from 0 to 1.
+// It reports false when the unit is not attacking.
+func (a *attacks) progress(id string, now time.Time) (float64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	at, ok := a.started[id]
+	if !ok {
+		return 0, false
+	}
+	elapsed := now.Sub(at)
+	if elapsed >= attackAnimation {
+		delete(a.started, id)
+		return 0, false
+	}
+	return float64(elapsed) / float64(attackAnimation), true
+}
+
+// handleServerEvent starts the animation of the attacks of remote units. The
+// local unit's attacks are animated as soon as the player attacks.
+func (a *attacks) handleServerEvent(myID string, event *events.Event, at time.Time) {
+	switch event.Type {
+	case events.Event_ATTACK:
+		if id := event.GetAttack().GetUnitID(); id != myID {
+			a.start(id, at)
+		}
+	case events.Event_DEATH:
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.started, event.GetDeath().GetUnitID())
+	}
+}
+
+// respawnTimer counts down until the local unit respawns.
+type respawnTimer struct {
+	mu sync.Mutex
+	at time.Time
+}
+
+func (r *respawnTimer) handleServerEvent(myID string, event *events.Event, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch event.Type {
+	case events.Event_DEATH:
+		death := event.GetDeath()
+		if death.UnitID == myID {
+			r.at = at.Add(time.Duration(death.RespawnIn * float64(time.Second)))
+		}
+	case events.Event_RESPAWN, events.Event_INIT:
+		r.at = time.Time{}
+	}
+}
+
+// draw shows the countdown while the local unit is dead.
+func (r *respawnTimer) draw(screen *e.Image, now time.Time) {
+	r.mu.Lock()
+	at := r.at
+	r.mu.Unlock()
+	if at.IsZero() {
+		return
+	}
+
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), overlayBackground, false)
+	left := math.Ceil(max(at.Sub(now), 0).Seconds())
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("YOU DIED - respawn in %.0f", left), 8, bounds.Dy()/2-8)
+}
+
+// attack sends an attack of the local unit and animates it right away. The
+// cooldown is enforced by the server, it is only mirrored here to not animate
+// attacks the server drops.
+func (g *Game) attack() {
+	unit, ok := g.World.Unit(g.World.MyID())
+	if !ok || unit.Action == events.Action_DEAD {
+		return
+	}
+	now := time.Now()
+	if now.Sub(g.lastAttack) < w.AttackCooldown {
+		return
+	}
+	g.lastAttack = now
+	g.attacks.start(unit.ID, now)
+
+	g.Conn.write(&events.Event{
+		Type: events.Event_ATTACK,
+		Data: &events.Event_Attack{
+			Attack: &events.EventAttack{
+				UnitID:    unit.ID,
+				Direction: unit.Direction,
+			},
+		},
+	})
+}
+
+// drawSwing draws the slash of an attack across the attack box, sweeping as
+// the animation progresses.
+func (g *Game) drawSwing(screen *e.Image, unit *events.Unit, progress float64) {
+	box := w.AttackBox(unit)
+	cx, cy := box.X+box.W/2, box.Y+box.H/2
+	reach := math.Max(box.W, box.H) / 2
+
+	// The slash is perpendicular to the attack direction.
+	angle := math.Pi/2 - math.Pi*progress
+	if unit.Direction == events.Direction_UP || unit.Direction == events.Direction_DOWN {
+		angle += math.Pi / 2
+	}
+	x0, y0 := g.camera.worldToScreen(cx-math.Cos(angle)*reach, cy-math.Sin(angle)*reach)
+	x1, y1 := g.camera.worldToScreen(cx+math.Cos(angle)*reach, cy+math.Sin(angle)*reach)
+	vector.StrokeLine(screen, float32(x0), float32(y0), float32(x1), float32(y1), float32(g.camera.zoom), swingColor, true)
+}
+
+// drawHealth draws the health bar above a living unit.
+func (g *Game) drawHealth(screen *e.Image, unit *events.Unit) {
+	if unit.MaxHp <= 0 || unit.Action == events.Action_DEAD {
+		return
+	}
+	x, y := g.camera.worldToScreen(unit.X, unit.Y-4)
+	width := w.SpriteWidth * g.camera.zoom
+	height := 2 * g.camera.zoom
+	filled := width * float64(unit.Hp) / float64(unit.MaxHp)
+
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), hpBackground, false)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(filled), float32(height), hpColor, false)
+}
@@ -0,0 +1,224 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	e "github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	events "github.com/patrick-me/game_one/proto"
+	"go.uber.org/zap"
+)
+
+const (
+	// Delay before the first reconnection attempt, doubled after every failed
+	// attempt up to maxReconnectDelay.
+	minReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay = 30 * time.Second
+
+	// Time without any event from the server after which the connection is
+	// considered lost. The server sends a snapshot several times a second.
+	readTimeout = 5 * time.Second
+)
+
+var errNotConnected = errors.New("not connected to the server")
+
+var overlayBackground = color.RGBA{A: 0x80}
+
+// connection keeps the game connected to the server. A lost connection is
+// dialed again with an exponential backoff. The player token identifies the
+// player, so the server gives back the same unit.
+type connection struct {
+	url       string
+	loginURL  string
+	gameToken string
+
+	// Player token and the file it is saved to. The token is renewed when
+	// the server rejects it.
+	token     string
+	tokenPath string
+	rejected  bool
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+
+	// Whether the server has sent the INIT event once.
+	joined bool
+
+	// The game loop and the connection reader both write, so writes are
+	// serialized.
+	writeMu sync.Mutex
+
+	// Failed attempts since the connection was lost, and when the next one
+	// starts.
+	attempts int
+	retryAt  time.Time
+}
+
+func newConnection(url, loginURL, gameToken string) *connection {
+	c := &connection{url: url, loginURL: loginURL, gameToken: gameToken, tokenPath: tokenPath()}
+
+	var err error
+	if c.token, err = loadToken(c.tokenPath); err != nil {
+		logger.Info("can't load player token", zap.String("path", c.tokenPath), zap.Error(err))
+	}
+	return c
+}
+
+// run connects to the server and hands its events to handle, reconnecting
+// whenever the connection is lost. It never returns.
+func (c *connection) run(handle func(event *events.Event, at time.Time)) {
+	delay := minReconnectDelay
+	for {
+		conn, err := c.dial()
+		if err != nil {
+			wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+			logger.Info("can't connect to server", zap.Duration("retry", wait), zap.Error(err))
+
+			c.mu.Lock()
+			c.attempts++
+			c.retryAt = time.Now().Add(wait)
+			c.mu.Unlock()
+
+			time.Sleep(wait)
+			delay = min(delay*2, maxReconnectDelay)
+			continue
+		}
+
+		delay = minReconnectDelay
+		c.read(conn, handle)
+	}
+}
+
+func (c *connection) dial() (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		Proxy:             http.ProxyFromEnvironment,
+		HandshakeTimeout:  45 * time.Second,
+		EnableCompression: true,
+	}
+
+	if c.token == "" || c.rejected {
+		if err := c.login(); err != nil {
+			return nil, err
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+c.token)
+	conn, resp, err := dialer.Dial(c.url, header)
+	if err != nil {
+		c.rejected = resp != nil && resp.StatusCode == http.StatusUnauthorized
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.attempts = 0
+	c.mu.Unlock()
+	return conn, nil
+}
+
+// login gets a new player token and saves it.
+func (c *connection) login() error {
+	token, err := login(c.loginURL, c.gameToken, c.token)
+	if err != nil {
+		return err
+	}
+	c.token, c.rejected = token, false
+
+	if err = os.WriteFile(c.tokenPath, []byte(token), 0600); err != nil {
+		logger.Info("can't save player token", zap.String("path", c.tokenPath), zap.Error(err))
+	}
+	return nil
+}
+
+// read handles the events of the connection until it fails.
+func (c *connection) read(conn *websocket.Conn, handle func(event *events.Event, at time.Time)) {
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		_, m, err := conn.ReadMessage()
+		if err != nil {
+			logger.Info("connection lost", zap.Error(err))
+			return
+		}
+
+		batch, err := events.UnmarshalFrame(m)
+		if err != nil {
+			logger.Info("can't unmarshal events", zap.Error(err))
+			continue
+		}
+		now := time.Now()
+		for _, event := range batch {
+			if event.Type == events.Event_INIT {
+				c.mu.Lock()
+				c.joined = true
+				c.mu.Unlock()
+			}
+			handle(event, now)
+		}
+	}
+}
+
+// write sends the events to the server.
+func (c *connection) write(evs ...*events.Event) error {
+	msg, err := events.MarshalFrame(evs...)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn == nil {
+		return errNotConnected
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return conn.WriteMessage(websocket.BinaryMessage, msg)
+}
+
+func (c *connection) connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// draw shows the reconnecting overlay while the connection is down.
+func (c *connection) draw(screen *e.Image) {
+	c.mu.Lock()
+	connected, resuming := c.conn != nil, c.joined
+	attempts, retryAt := c.attempts, c.retryAt
+	c.mu.Unlock()
+	if connected {
+		return
+	}
+
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), overlayBackground, false)
+
+	status := "connecting..."
+	if resuming {
+		status = "reconnecting..."
+	}
+	if attempts > 0 {
+		retry := max(time.Until(retryAt), 0).Round(time.Second)
+		status += fmt.Sprintf(" attempt %d, next in %s", attempts, retry)
+	}
+	ebitenutil.DebugPrintAt(screen, status, 8, bounds.Dy()/2-8)
+}
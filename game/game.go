@@ -1,17 +1,18 @@
 package game
 
 import (
+	"context"
 	"fmt"
-	"github.com/gorilla/websocket"
 	e "github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/joho/godotenv"
 	_ "github.com/patrick-me/game_one/proto"
 	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
 	w "github.com/patrick-me/game_one/world"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/proto"
-	"net/http"
+	"math"
 	"os"
 	"sort"
 	"strconv"
@@ -23,72 +24,125 @@ type Game struct {
 	ScreenHeight  int
 	Frame         int
 	World         *w.World
-	Conn          *websocket.Conn
+	Conn          *connection
 	BackgroundImg *e.Image
 	ImgPool       map[string]*e.Image
+
+	predictor    *predictor
+	interpolator *interpolator
+	camera       *camera
+	controls     *controls
+	menu         *bindingsMenu
+	attacks      *attacks
+	respawn      *respawnTimer
+
+	// Way to the destination clicked or tapped by the player, nil when there
+	// is none.
+	route *route
+
+	// When the local unit last attacked.
+	lastAttack time.Time
+
+	// Whether the debug overlay is shown.
+	debug bool
 }
 
 var world *w.World
+var prediction *predictor
+var interpolation *interpolator
+var attackAnims *attacks
+var respawn *respawnTimer
+var snapshotBuffer *snapshots
 var frame int
 var backgroundImg *e.Image
 var imgPool map[string]*e.Image
-var c *websocket.Conn
+var server *connection
 var logger *zap.Logger
 
 func init() {
+	logger, _ = zap.NewProduction()
+	defer logger.Sync()
+
 	if err := godotenv.Load(); err != nil {
 		logger.Info("No .env file found")
 	}
 
-	world = &w.World{
-		IsServer: false,
-		Units:    make(map[string]*events.Unit),
+	world = w.New(false)
+	prediction = &predictor{}
+	interpolation = newInterpolator(interpolationDelay())
+	attackAnims = newAttacks()
+	respawn = &respawnTimer{}
+	snapshotBuffer = newSnapshots()
+
+	tiles, err := tilemap.Load(tilemap.Path())
+	if err != nil {
+		logger.Fatal("can't load map", zap.Error(err))
 	}
+	world.SetMap(tiles)
 
-	backgroundImg, _, _ = ebitenutil.NewImageFromFile("resources/frames/bg.png")
+	backgroundImg, err = renderMap(tiles)
+	if err != nil {
+		logger.Fatal("can't render map", zap.Error(err))
+	}
 	imgPool = make(map[string]*e.Image)
 
-	c = connectToServer()
+	server = newConnection(os.Getenv("CONNECTION_URL"), os.Getenv("LOGIN_URL"), os.Getenv("AUTH_TOKEN"))
+	go server.run(handleServerEvent)
+}
 
-	logger, _ = zap.NewProduction()
-	defer logger.Sync()
+func handleServerEvent(event *events.Event, at time.Time) {
+	if event.Type == events.Event_INIT {
+		// A new connection starts over from the state in the event.
+		snapshotBuffer = newSnapshots()
+		interpolation.reset()
+		prediction.reset()
+	}
 
-}
+	myID := world.MyID()
 
-func connectToServer() *websocket.Conn {
-	header := http.Header{}
-	header.Set("Authorization", os.Getenv("AUTH_TOKEN"))
-	dialer := websocket.Dialer{
-		Proxy:             http.ProxyFromEnvironment,
-		HandshakeTimeout:  45 * time.Second,
-		EnableCompression: true,
+	if event.Type == events.Event_SHUTDOWN {
+		logger.Info("server is shutting down", zap.String("reason", event.GetShutdown().Reason))
+		return
 	}
 
-	conn, _, err := dialer.Dial(os.Getenv("CONNECTION_URL"), header)
+	if event.Type != events.Event_SNAPSHOT {
+		interpolation.record(myID, event, at)
+		attackAnims.handleServerEvent(myID, event, at)
+		respawn.handleServerEvent(myID, event, at)
+		prediction.handleServerEvent(world, event)
+		return
+	}
 
+	snapshot := event.GetSnapshot()
+	units, err := snapshotBuffer.apply(snapshot)
 	if err != nil {
-		logger.Info("can't connect to server", zap.Error(err))
+		logger.Info("can't apply snapshot", zap.Uint64("tick", snapshot.Tick), zap.Error(err))
+		return
 	}
 
-	go func(cn *websocket.Conn) {
-		defer cn.Close()
-
-		for {
-			_, m, err2 := cn.ReadMessage()
-			if err2 != nil {
-				logger.Info("can't read event", zap.Error(err))
-			}
-			var event events.Event
-			proto.Unmarshal(m, &event)
-			world.HandleEvent(&event)
-		}
-	}(conn)
+	ack := &events.Event{
+		Type: events.Event_ACK,
+		Data: &events.Event_Ack{
+			Ack: &events.EventAck{
+				Tick: snapshot.Tick,
+			},
+		},
+	}
+	if err = server.write(ack); err != nil {
+		logger.Info("can't acknowledge snapshot", zap.Error(err))
+	}
 
-	return conn
+	interpolation.recordState(myID, units, snapshot.Tick, at)
+	prediction.applyState(world, units)
 }
 
 func NewGame() (*Game, error) {
-	go world.Evolve()
+	go world.Run(context.Background())
+
+	keys, err := loadBindings(bindingsPath())
+	if err != nil {
+		logger.Info("can't load bindings, using defaults", zap.Error(err))
+	}
 
 	return &Game{
 		ScreenWidth:   320,
@@ -97,61 +151,138 @@ func NewGame() (*Game, error) {
 		World:         world,
 		BackgroundImg: backgroundImg,
 		ImgPool:       imgPool,
-		Conn:          c,
+		Conn:          server,
+		predictor:     prediction,
+		interpolator:  interpolation,
+		camera:        newCamera(320, 320),
+		controls:      newControls(keys, 320, 320),
+		menu:          &bindingsMenu{bindings: keys},
+		attacks:       attackAnims,
+		respawn:       respawn,
+		debug:         true,
 	}, nil
 }
 
+// bindingsPath returns the key bindings file set in BINDINGS_PATH.
+func bindingsPath() string {
+	if path := os.Getenv("BINDINGS_PATH"); path != "" {
+		return path
+	}
+	return defaultBindingsPath
+}
+
+// interpolationDelay reads how far in the past remote units are rendered.
+func interpolationDelay() time.Duration {
+	value := os.Getenv("INTERPOLATION_DELAY")
+	if value == "" {
+		return defaultInterpolationDelay
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Info("invalid interpolation delay", zap.String("delay", value), zap.Error(err))
+		return defaultInterpolationDelay
+	}
+	return delay
+}
+
 func (g *Game) Update() error {
-	if e.IsKeyPressed(e.KeyD) || e.IsKeyPressed(e.KeyRight) {
-		sendEvent(g, events.Direction_RIGHT)
-		return nil
+	g.updateCamera()
+
+	in := g.controls.update()
+	if g.menu.update(in) || !g.Conn.connected() {
+		in = &intent{}
+		g.route = nil
+	} else if in.justPressed(actionToggleDebug) {
+		g.debug = !g.debug
 	}
 
-	if e.IsKeyPressed(e.KeyA) || e.IsKeyPressed(e.KeyLeft) {
-		sendEvent(g, events.Direction_LEFT)
-		return nil
+	if in.justPressed(actionAttack) {
+		g.attack()
 	}
 
-	if e.IsKeyPressed(e.KeyW) || e.IsKeyPressed(e.KeyUp) {
-		sendEvent(g, events.Direction_UP)
+	myID := g.World.MyID()
+	unit, ok := g.World.Unit(myID)
+	if ok && unit.Action == events.Action_DEAD {
+		g.route = nil
 		return nil
 	}
 
-	if e.IsKeyPressed(e.KeyS) || e.IsKeyPressed(e.KeyDown) {
-		sendEvent(g, events.Direction_DOWN)
+	if in.tapped {
+		g.walkTo(in.tapX, in.tapY)
+	}
+
+	if in.moveX != 0 || in.moveY != 0 {
+		// Moving by hand cancels the walk to a destination.
+		g.route = nil
+		sendEvent(g, in.moveX, in.moveY)
 		return nil
 	}
 
-	unit, ok := g.World.Units[g.World.MyID]
+	if ok {
+		if vx, vy, walking := g.followRoute(unit, time.Now()); walking {
+			sendEvent(g, vx, vy)
+			return nil
+		}
+	}
+
 	if ok && unit.Action == events.Action_RUN {
+		seq := g.predictor.apply(g.World, events.Action_IDLE, 0, 0, tickSeconds())
 		event := events.Event{
 			Type: events.Event_IDLE,
 			Data: &events.Event_Idle{
 				Idle: &events.EventIdle{
-					UnitID: g.World.MyID,
+					UnitID: myID,
+					Seq:    seq,
 				},
 			},
 		}
-		msg, _ := proto.Marshal(&event)
-		g.Conn.WriteMessage(websocket.BinaryMessage, msg)
+		g.Conn.write(&event)
 		return nil
 	}
 
 	return nil
 }
 
-func sendEvent(g *Game, direction events.Direction) {
+// tickSeconds returns the length of a game update, which is also how long an
+// input lasts.
+func tickSeconds() float64 {
+	return 1 / float64(e.TPS())
+}
+
+// updateCamera follows the local unit and applies the zoom controls.
+func (g *Game) updateCamera() {
+	bounds := g.World.Bounds()
+
+	if _, dy := e.Wheel(); dy != 0 {
+		g.camera.zoomBy(math.Pow(1.1, dy), bounds)
+	}
+	if inpututil.IsKeyJustPressed(e.KeyEqual) || inpututil.IsKeyJustPressed(e.KeyKPAdd) {
+		g.camera.zoomBy(2, bounds)
+	}
+	if inpututil.IsKeyJustPressed(e.KeyMinus) || inpututil.IsKeyJustPressed(e.KeyKPSubtract) {
+		g.camera.zoomBy(0.5, bounds)
+	}
+
+	if unit, ok := g.World.Unit(g.World.MyID()); ok {
+		g.camera.follow(unit.X+w.SpriteWidth/2, unit.Y+w.SpriteHeight/2, bounds)
+	}
+}
+
+func sendEvent(g *Game, vx, vy float64) {
+	seq := g.predictor.apply(g.World, events.Action_RUN, vx, vy, tickSeconds())
 	event := events.Event{
 		Type: events.Event_MOVE,
 		Data: &events.Event_Move{
 			Move: &events.EventMove{
-				UnitID:    g.World.MyID,
-				Direction: direction,
+				UnitID:    g.World.MyID(),
+				Direction: w.Facing(vx, vy, events.Direction_RIGHT),
+				Seq:       seq,
+				Vx:        vx,
+				Vy:        vy,
 			},
 		},
 	}
-	msg, _ := proto.Marshal(&event)
-	g.Conn.WriteMessage(websocket.BinaryMessage, msg)
+	g.Conn.write(&event)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (_, _ int) {
@@ -161,9 +292,20 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (_, _ int) {
 func (g *Game) Draw(screen *e.Image) {
 	g.Frame++
 
-	screen.DrawImage(g.BackgroundImg, nil)
+	view := g.camera.geoM()
+	background := &e.DrawImageOptions{GeoM: view}
+	screen.DrawImage(g.BackgroundImg, background)
+	g.drawRoute(screen)
+
+	myID := g.World.MyID()
+	now := time.Now()
 	unitList := []*events.Unit{}
-	for _, unit := range g.World.Units {
+	for id, unit := range g.World.Units() {
+		if id != myID {
+			if x, y, ok := g.interpolator.position(id, now); ok {
+				unit.X, unit.Y = x, y
+			}
+		}
 		unitList = append(unitList, unit)
 	}
 
@@ -180,7 +322,13 @@ func (g *Game) Draw(screen *e.Image) {
 			op.GeoM.Translate(16, 0)
 		}
 
-		op.GeoM.Translate(unit.X, unit.Y)
+		attack, attacking := g.attacks.progress(unit.ID, now)
+		if attacking {
+			// Lunge towards the target and back.
+			dx, dy := w.Velocity(unit.Direction)
+			lunge := 2 * math.Sin(math.Pi*attack)
+			op.GeoM.Translate(dx*lunge, dy*lunge)
+		}
 
 		var a string
 		switch unit.Action {
@@ -188,8 +336,21 @@ func (g *Game) Draw(screen *e.Image) {
 			a = "run"
 		case events.Action_IDLE:
 			a = "idle"
-
+		case events.Action_DEAD:
+			// Lying on the ground.
+			a = "idle"
+			spriteIndex = 0
+			op.GeoM.Rotate(math.Pi / 2)
+			op.GeoM.Translate(w.SpriteHeight, w.SpriteHeight-w.SpriteWidth)
+			op.ColorScale.Scale(0.6, 0.6, 0.6, 1)
+		}
+		if unit.Invulnerable && g.Frame/4%2 == 0 {
+			// Blink while protected after spawning.
+			op.ColorScale.ScaleAlpha(0.4)
 		}
+
+		op.GeoM.Translate(unit.X, unit.Y)
+		op.GeoM.Concat(view)
 		path := "resources/frames/" + unit.SpriteName + "_" + a + "_anim_f" +
 			strconv.Itoa(spriteIndex) + ".png"
 
@@ -202,6 +363,18 @@ func (g *Game) Draw(screen *e.Image) {
 		}
 
 		screen.DrawImage(img, op)
+
+		if attacking {
+			g.drawSwing(screen, unit, attack)
+		}
+		g.drawHealth(screen, unit)
+	}
+
+	if g.debug {
 		ebitenutil.DebugPrint(screen, fmt.Sprintf("TPS: %0.2f, FPS: %0.2f", e.ActualTPS(), e.ActualFPS()))
 	}
+	g.respawn.draw(screen, now)
+	g.controls.touch.draw(screen)
+	g.menu.draw(screen)
+	g.Conn.draw(screen)
 }
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"math"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	w "github.com/patrick-me/game_one/world"
+)
+
+// Analog stick tilt below which the stick is considered centered.
+const stickDeadZone = 0.2
+
+// intent is what the player wants to do during a tick.
+type intent struct {
+	// Movement vector of length up to 1.
+	moveX, moveY float64
+
+	// Actions pressed during this tick.
+	pressed map[action]bool
+
+	// Screen position clicked or tapped during this tick, if any.
+	tapped     bool
+	tapX, tapY float64
+}
+
+func (in *intent) tap(x, y float64) {
+	in.tapped = true
+	in.tapX, in.tapY = x, y
+}
+
+func (in *intent) justPressed(a action) bool {
+	return in.pressed[a]
+}
+
+// inputSource is an input device adding its state to the intent.
+type inputSource interface {
+	read(b *bindings, in *intent)
+}
+
+// controls merge the keyboard, the gamepads and the touch screen into a single
+// intent, so the game doesn't care which device the player uses.
+type controls struct {
+	bindings *bindings
+	sources  []inputSource
+	touch    *touchControls
+}
+
+func newControls(b *bindings, screenWidth, screenHeight int) *controls {
+	touch := newTouchControls(screenWidth, screenHeight)
+	return &controls{
+		bindings: b,
+		sources:  []inputSource{keyboard{}, gamepads{}, mouse{}, touch},
+		touch:    touch,
+	}
+}
+
+// update reads every device. The first device with a movement wins.
+func (c *controls) update() *intent {
+	in := &intent{pressed: make(map[action]bool)}
+	for _, source := range c.sources {
+		source.read(c.bindings, in)
+	}
+	in.moveX, in.moveY = w.Normalize(in.moveX, in.moveY)
+	return in
+}
+
+// keyboard reads the bound keys, key combinations move diagonally.
+type keyboard struct{}
+
+func (keyboard) read(b *bindings, in *intent) {
+	pressed := func(a action) bool {
+		for _, key := range b.keys[a] {
+			if e.IsKeyPressed(key) {
+				return true
+			}
+		}
+		return false
+	}
+	for a, keys := range b.keys {
+		for _, key := range keys {
+			if inpututil.IsKeyJustPressed(key) {
+				in.pressed[a] = true
+			}
+		}
+	}
+
+	if in.moveX != 0 || in.moveY != 0 {
+		return
+	}
+	in.moveX, in.moveY = axes(pressed)
+}
+
+// mouse reads the clicks of the left button.
+type mouse struct{}
+
+func (mouse) read(_ *bindings, in *intent) {
+	if inpututil.IsMouseButtonJustPressed(e.MouseButtonLeft) {
+		x, y := e.CursorPosition()
+		in.tap(float64(x), float64(y))
+	}
+}
+
+// gamepads reads the left stick and the bound buttons of every gamepad with
+// the standard layout.
+type gamepads struct{}
+
+func (gamepads) read(b *bindings, in *intent) {
+	for _, id := range e.AppendGamepadIDs(nil) {
+		if !e.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+
+		for a, buttons := range b.buttons {
+			for _, button := range buttons {
+				if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+					in.pressed[a] = true
+				}
+			}
+		}
+
+		if in.moveX != 0 || in.moveY != 0 {
+			continue
+		}
+		x := e.StandardGamepadAxisValue(id, e.StandardGamepadAxisLeftStickHorizontal)
+		y := e.StandardGamepadAxisValue(id, e.StandardGamepadAxisLeftStickVertical)
+		if math.Hypot(x, y) > stickDeadZone {
+			in.moveX, in.moveY = x, y
+			continue
+		}
+		in.moveX, in.moveY = axes(func(a action) bool {
+			for _, button := range b.buttons[a] {
+				if e.IsStandardGamepadButtonPressed(id, button) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+}
+
+// axes turns the pressed direction actions into a movement vector.
+func axes(pressed func(a action) bool) (x, y float64) {
+	if pressed(actionMoveRight) {
+		x++
+	}
+	if pressed(actionMoveLeft) {
+		x--
+	}
+	if pressed(actionMoveUp) {
+		y--
+	}
+	if pressed(actionMoveDown) {
+		y++
+	}
+	return x, y
+}
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"sync"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+	w "github.com/patrick-me/game_one/world"
+)
+
+// Default time remote units are rendered in the past. It spans two server
+// state broadcasts so there is almost always a snapshot on both sides of the
+// render time.
+const defaultInterpolationDelay = 200 * time.Millisecond
+
+// snapshot is the position of a remote unit at the time it was received, or
+// for world snapshots at their tick on the server timeline.
+type snapshot struct {
+	at   time.Time
+	x, y float64
+}
+
+// interpolator buffers the snapshots of remote units and renders them between
+// the two snapshots bracketing the delayed render time.
+type interpolator struct {
+	mu      sync.Mutex
+	delay   time.Duration
+	buffers map[string][]snapshot
+
+	// Local time of the server tick zero. It is the earliest one the world
+	// snapshots arrived by, the one of the least delayed snapshot, and is set
+	// once synced.
+	epoch  time.Time
+	synced bool
+}
+
+func newInterpolator(delay time.Duration) *interpolator {
+	return &interpolator{
+		delay:   delay,
+		buffers: make(map[string][]snapshot),
+	}
+}
+
+// record buffers the positions of remote units carried by a server event.
+func (i *interpolator) record(myID string, event *events.Event, at time.Time) {
+	switch event.Type {
+	case events.Event_INIT:
+		init := event.GetInit()
+		for id, unit := range init.GetUnits() {
+			if id != init.PlayerID {
+				i.push(id, at, unit.X, unit.Y)
+			}
+		}
+	case events.Event_CONNECT:
+		unit := event.GetConnect().GetUnit()
+		if unit != nil && unit.ID != myID {
+			i.push(unit.ID, at, unit.X, unit.Y)
+		}
+	case events.Event_ENTER_VIEW:
+		unit := event.GetEnterView().GetUnit()
+		if unit != nil && unit.ID != myID {
+			i.push(unit.ID, at, unit.X, unit.Y)
+		}
+	case events.Event_DISCONNECT:
+		i.remove(event.GetDisconnect().GetUnitID())
+	case events.Event_LEAVE_VIEW:
+		i.remove(event.GetLeaveView().GetUnitID())
+	case events.Event_RESPAWN:
+		// Jump to the spawn point instead of sliding there.
+		unit := event.GetRespawn().GetUnit()
+		if unit != nil && unit.ID != myID {
+			i.remove(unit.ID)
+			i.push(unit.ID, at, unit.X, unit.Y)
+		}
+	}
+}
+
+// recordState buffers the positions of remote units from the world snapshot
+// of the tick received at, forgetting the units missing from it. The positions
+// are timed by the tick rather than by their arrival, so the network jitter
+// doesn't show.
+func (i *interpolator) recordState(myID string, units map[string]*events.Unit, tick uint64, at time.Time) {
+	i.mu.Lock()
+	for id := range i.buffers {
+		if _, ok := units[id]; !ok {
+			delete(i.buffers, id)
+		}
+	}
+	serverTime := time.Duration(tick) * w.TickDuration
+	if epoch := at.Add(-serverTime); !i.synced || epoch.Before(i.epoch) {
+		i.epoch, i.synced = epoch, true
+	}
+	at = i.epoch.Add(serverTime)
+	i.mu.Unlock()
+
+	for id, unit := range units {
+		if id != myID {
+			i.push(id, at, unit.X, unit.Y)
+		}
+	}
+}
+
+func (i *interpolator) push(id string, at time.Time, x, y float64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Positions timed by their arrival may be ahead of the server timeline,
+	// the ones not older than the new position are replaced by it.
+	buffer := i.buffers[id]
+	for len(buffer) > 0 && !buffer[len(buffer)-1].at.Before(at) {
+		buffer = buffer[:len(buffer)-1]
+	}
+	buffer = append(buffer, snapshot{at: at, x: x, y: y})
+
+	// Keep a single snapshot older than anything that can still be rendered.
+	renderAt := at.Add(-i.delay)
+	for len(buffer) > 2 && !buffer[1].at.After(renderAt) {
+		buffer = buffer[1:]
+	}
+	i.buffers[id] = buffer
+}
+
+// reset forgets every unit and the server timeline.
+func (i *interpolator) reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.buffers = make(map[string][]snapshot)
+	i.synced = false
+}
+
+func (i *interpolator) remove(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.buffers, id)
+}
+
+// position returns the interpolated position of a remote unit at now.
+func (i *interpolator) position(id string, now time.Time) (x, y float64, ok bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	buffer := i.buffers[id]
+	if len(buffer) == 0 {
+		return 0, 0, false
+	}
+
+	renderAt := now.Add(-i.delay)
+	if !renderAt.After(buffer[0].at) {
+		return buffer[0].x, buffer[0].y, true
+	}
+
+	for k := 1; k < len(buffer); k++ {
+		from, to := buffer[k-1], buffer[k]
+		if renderAt.After(to.at) {
+			continue
+		}
+		t := float64(renderAt.Sub(from.at)) / float64(to.at.Sub(from.at))
+		return from.x + (to.x-from.x)*t, from.y + (to.y-from.y)*t, true
+	}
+
+	last := buffer[len(buffer)-1]
+	return last.x, last.y, true
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"go.uber.org/zap"
+)
+
+var menuBackground = color.RGBA{A: 0xc0}
+
+// bindingsMenu is the in-game screen listing the actions. The selected action
+// is rebound to the next key or gamepad button pressed, and the bindings are
+// saved right away.
+type bindingsMenu struct {
+	bindings *bindings
+
+	open      bool
+	selected  int
+	capturing bool
+
+	// Error of the last save, shown at the bottom of the screen.
+	status string
+}
+
+// update handles the menu input. It reports whether the menu is open, in
+// which case the game ignores the player input.
+func (m *bindingsMenu) update(in *intent) bool {
+	if !m.open {
+		m.open = in.justPressed(actionBindingsMenu)
+		return m.open
+	}
+
+	if m.capturing {
+		m.capture()
+		return true
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(e.KeyEscape) || in.justPressed(actionBindingsMenu):
+		m.open = false
+	case inpututil.IsKeyJustPressed(e.KeyArrowUp) || in.justPressed(actionMoveUp):
+		m.selected = (m.selected + len(actions) - 1) % len(actions)
+	case inpututil.IsKeyJustPressed(e.KeyArrowDown) || in.justPressed(actionMoveDown):
+		m.selected = (m.selected + 1) % len(actions)
+	case inpututil.IsKeyJustPressed(e.KeyEnter) || in.justPressed(actionAttack):
+		m.capturing = true
+	}
+	return true
+}
+
+// capture binds the selected action to the first key or button pressed.
+// Escape cancels.
+func (m *bindingsMenu) capture() {
+	a := actions[m.selected]
+
+	if keys := inpututil.AppendJustPressedKeys(nil); len(keys) > 0 {
+		m.capturing = false
+		if keys[0] == e.KeyEscape {
+			return
+		}
+		m.bindings.bindKey(a, keys[0])
+		m.save()
+		return
+	}
+
+	for _, id := range e.AppendGamepadIDs(nil) {
+		if buttons := inpututil.AppendJustPressedStandardGamepadButtons(id, nil); len(buttons) > 0 {
+			m.capturing = false
+			m.bindings.bindButton(a, buttons[0])
+			m.save()
+			return
+		}
+	}
+}
+
+func (m *bindingsMenu) save() {
+	m.status = ""
+	if err := m.bindings.save(); err != nil {
+		logger.Info("can't save bindings", zap.String("path", m.bindings.path), zap.Error(err))
+		m.status = "can't save " + m.bindings.path
+	}
+}
+
+func (m *bindingsMenu) draw(screen *e.Image) {
+	if !m.open {
+		return
+	}
+
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), menuBackground, false)
+	ebitenutil.DebugPrintAt(screen, "KEY BINDINGS (Enter: rebind, Esc: close)", 8, 8)
+
+	for i, a := range actions {
+		cursor := "  "
+		if i == m.selected {
+			cursor = "> "
+		}
+
+		bound := m.describe(a)
+		if i == m.selected && m.capturing {
+			bound = "press a key or a button..."
+		}
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%s%-14s %s", cursor, a, bound), 8, 32+16*i)
+	}
+
+	if m.status != "" {
+		ebitenutil.DebugPrintAt(screen, m.status, 8, bounds.Dy()-24)
+	}
+}
+
+// describe lists the keys and buttons bound to the action.
+func (m *bindingsMenu) describe(a action) string {
+	var names []string
+	for _, key := range m.bindings.keys[a] {
+		names = append(names, key.String())
+	}
+	for _, button := range m.bindings.buttons[a] {
+		names = append(names, "pad:"+buttonNames[button])
+	}
+	return strings.Join(names, ", ")
+}
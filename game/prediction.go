@@ -0,0 +1,127 @@
+package game
+
+import (
+	"sync"
+
+	events "github.com/patrick-me/game_one/proto"
+	w "github.com/patrick-me/game_one/world"
+)
+
+// Upper bound of inputs waiting for the server acknowledgement.
+const maxPendingInputs = 256
+
+// input is a single tick of the local player's input, lasting dt seconds.
+type input struct {
+	seq    uint32
+	action events.Action
+	vx, vy float64
+	dt     float64
+}
+
+// predictor applies the local player's inputs immediately and reconciles the
+// predicted unit with the authoritative state sent by the server.
+type predictor struct {
+	mu      sync.Mutex
+	seq     uint32
+	pending []input
+}
+
+// apply records the input, applies it to the local unit and returns its
+// sequence number to be sent to the server.
+func (p *predictor) apply(world *w.World, action events.Action, vx, vy, dt float64) uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.seq++
+	vx, vy = w.Normalize(vx, vy)
+	in := input{seq: p.seq, action: action, vx: vx, vy: vy, dt: dt}
+	p.pending = append(p.pending, in)
+	if len(p.pending) > maxPendingInputs {
+		p.pending = p.pending[len(p.pending)-maxPendingInputs:]
+	}
+
+	world.UpdateUnit(world.MyID(), in.applyTo)
+	return in.seq
+}
+
+// applyTo sets the unit in motion the way the server does for the input.
+func (in input) applyTo(unit *events.Unit) {
+	unit.Action = in.action
+	unit.Vx, unit.Vy = in.vx, in.vy
+	unit.Direction = w.Facing(in.vx, in.vy, unit.Direction)
+}
+
+// reconcile resets the local unit to the server state and replays the inputs
+// the server has not processed yet.
+func (p *predictor) reconcile(world *w.World, state *events.Unit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := 0
+	for i < len(p.pending) && p.pending[i].seq <= state.LastInputSeq {
+		i++
+	}
+	p.pending = p.pending[i:]
+
+	world.UpdateUnit(state.ID, func(unit *events.Unit) {
+		unit.X = state.X
+		unit.Y = state.Y
+		unit.Speed = state.Speed
+		unit.Action = state.Action
+		unit.Direction = state.Direction
+		unit.LastInputSeq = state.LastInputSeq
+		unit.Vx = state.Vx
+		unit.Vy = state.Vy
+		unit.Hp = state.Hp
+		unit.MaxHp = state.MaxHp
+		unit.Invulnerable = state.Invulnerable
+
+		for _, in := range p.pending {
+			in.applyTo(unit)
+			world.MoveUnit(unit, in.dt)
+		}
+	})
+}
+
+// reset drops the pending inputs, which the server won't acknowledge after a
+// reconnection.
+func (p *predictor) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending = nil
+}
+
+// handleServerEvent passes an event received from the server to the world,
+// keeping the local unit under control of the predictor.
+func (p *predictor) handleServerEvent(world *w.World, event *events.Event) {
+	myID := world.MyID()
+
+	switch event.Type {
+	case events.Event_MOVE:
+		if event.GetMove().GetUnitID() == myID {
+			return
+		}
+	case events.Event_IDLE:
+		if event.GetIdle().GetUnitID() == myID {
+			return
+		}
+	}
+
+	world.HandleEvent(event)
+}
+
+// applyState passes the authoritative state of the units to the world and
+// reconciles the local unit with it.
+func (p *predictor) applyState(world *w.World, units map[string]*events.Unit) {
+	myID := world.MyID()
+
+	others := make(map[string]*events.Unit, len(units))
+	for id, unit := range units {
+		if id == myID {
+			p.reconcile(world, unit)
+			continue
+		}
+		others[id] = unit
+	}
+	world.ApplyState(others)
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
+	w "github.com/patrick-me/game_one/world"
+)
+
+// Radius of the destination marker in world pixels.
+const markerRadius = 4
+
+var markerColor = color.RGBA{R: 0xff, G: 0xe0, B: 0x40, A: 0xc0}
+
+// route is the way of the local unit to a destination the player clicked or
+// tapped.
+type route struct {
+	// Destination in the world, where the feet of the unit go.
+	x, y float64
+
+	// Unit positions left to walk through, the last one puts the feet on the
+	// destination.
+	waypoints []tilemap.Point
+
+	// The unit gives up when stuck, e.g. behind another unit, until then.
+	deadline time.Time
+}
+
+// walkTo plans the way of the local unit to the screen position, around the
+// walls. It reports false when the place can't be reached.
+func (g *Game) walkTo(screenX, screenY float64) bool {
+	unit, ok := g.World.Unit(g.World.MyID())
+	if !ok || unit.Action == events.Action_DEAD || unit.Speed <= 0 {
+		return false
+	}
+
+	x, y := g.camera.screenToWorld(screenX, screenY)
+	feetX, feetY := w.Hitbox(&events.Unit{}).Center()
+	waypoints, ok := g.World.FindPath(unit.X, unit.Y, x-feetX, y-feetY)
+	if !ok {
+		return false
+	}
+
+	length, fromX, fromY := 0.0, unit.X, unit.Y
+	for _, p := range waypoints {
+		length += math.Hypot(p.X-fromX, p.Y-fromY)
+		fromX, fromY = p.X, p.Y
+	}
+	// The last waypoint is off the clicked spot when the unit doesn't fit
+	// there.
+	g.route = &route{
+		x:         fromX + feetX,
+		y:         fromY + feetY,
+		waypoints: waypoints,
+		deadline:  time.Now().Add(time.Second + time.Duration(2*length/unit.Speed*float64(time.Second))),
+	}
+	return true
+}
+
+// followRoute returns the movement taking the unit to the next waypoint of
+// the route. It reports false and drops the route once the unit has arrived
+// or is stuck.
+func (g *Game) followRoute(unit *events.Unit, now time.Time) (vx, vy float64, ok bool) {
+	r := g.route
+	if r == nil {
+		return 0, 0, false
+	}
+	if now.After(r.deadline) {
+		g.route = nil
+		return 0, 0, false
+	}
+
+	// Close enough when the unit would reach the waypoint during this tick.
+	reach := unit.Speed * tickSeconds()
+	for len(r.waypoints) > 0 {
+		dx, dy := r.waypoints[0].X-unit.X, r.waypoints[0].Y-unit.Y
+		if distance := math.Hypot(dx, dy); distance > reach {
+			return dx / distance, dy / distance, true
+		}
+		r.waypoints = r.waypoints[1:]
+	}
+	g.route = nil
+	return 0, 0, false
+}
+
+// drawRoute marks the destination of the local unit on the ground.
+func (g *Game) drawRoute(screen *e.Image) {
+	if g.route == nil {
+		return
+	}
+	x, y := g.camera.worldToScreen(g.route.x, g.route.y)
+	radius := float32(markerRadius * g.camera.zoom)
+	vector.StrokeCircle(screen, float32(x), float32(y), radius, float32(g.camera.zoom), markerColor, true)
+	vector.StrokeLine(screen, float32(x)-radius/2, float32(y), float32(x)+radius/2, float32(y), 1, markerColor, true)
+	vector.StrokeLine(screen, float32(x), float32(y)-radius/2, float32(x), float32(y)+radius/2, 1, markerColor, true)
+}
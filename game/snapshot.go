@@ -0,0 +1,48 @@
+package game
+
+import (
+	"errors"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+// Number of received snapshots kept as candidate delta baselines.
+const snapshotHistory = 32
+
+var (
+	errStaleSnapshot   = errors.New("snapshot is older than the latest one")
+	errMissingBaseline = errors.New("snapshot baseline is missing")
+)
+
+// snapshots rebuilds the full world state from the delta snapshots sent by
+// the server. It is only used by the connection reader goroutine.
+type snapshots struct {
+	latest  uint64
+	history map[uint64]map[string]*events.Unit
+}
+
+func newSnapshots() *snapshots {
+	return &snapshots{history: make(map[uint64]map[string]*events.Unit)}
+}
+
+// apply patches the baseline of the snapshot and returns the full state of
+// the units at the snapshot tick.
+func (s *snapshots) apply(snapshot *events.EventSnapshot) (map[string]*events.Unit, error) {
+	if snapshot.Tick <= s.latest {
+		return nil, errStaleSnapshot
+	}
+
+	var base map[string]*events.Unit
+	if snapshot.BaseTick != 0 {
+		var ok bool
+		if base, ok = s.history[snapshot.BaseTick]; !ok {
+			return nil, errMissingBaseline
+		}
+	}
+
+	units := events.Patch(base, snapshot.Units, snapshot.Removed)
+	s.latest = snapshot.Tick
+	s.history[snapshot.Tick] = units
+	events.TrimHistory(s.history, snapshotHistory)
+	return units, nil
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"image"
+	"path/filepath"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/patrick-me/game_one/tilemap"
+)
+
+// renderMap draws the visible tile layers of the map into a single image. The
+// map is static, so it is rendered once and drawn as a background.
+func renderMap(m *tilemap.Map) (*e.Image, error) {
+	tilesets := make(map[*tilemap.Tileset]*e.Image)
+	for _, ts := range m.Tilesets {
+		img, _, err := ebitenutil.NewImageFromFile(filepath.Join(m.Dir, ts.Image))
+		if err != nil {
+			return nil, err
+		}
+		tilesets[ts] = img
+	}
+
+	background := e.NewImage(int(m.PixelWidth()), int(m.PixelHeight()))
+	for _, layer := range m.Layers {
+		if layer.Type != tilemap.TileLayer || !layer.Visible {
+			continue
+		}
+		for y := 0; y < m.Height; y++ {
+			for x := 0; x < m.Width; x++ {
+				gid := layer.Tile(x, y)
+				if gid == 0 {
+					continue
+				}
+				ts, ok := m.Tileset(gid)
+				if !ok {
+					continue
+				}
+				sx, sy, sw, sh := ts.TileRect(gid)
+				tile := tilesets[ts].SubImage(image.Rect(sx, sy, sx+sw, sy+sh)).(*e.Image)
+
+				op := &e.DrawImageOptions{}
+				// Tiles bigger than the grid are anchored at the bottom left.
+				op.GeoM.Translate(float64(x*m.TileWidth), float64((y+1)*m.TileHeight-sh))
+				op.ColorScale.ScaleAlpha(float32(layer.Opacity))
+				background.DrawImage(tile, op)
+			}
+		}
+	}
+	return background, nil
+}
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	e "github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	joystickRadius = 32
+	buttonRadius   = 18
+
+	// A touch released within tapTicks, not having moved more than tapSlop
+	// pixels, is a tap.
+	tapTicks = 15
+	tapSlop  = 8
+)
+
+var (
+	touchColor       = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x60}
+	touchActiveColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xa0}
+)
+
+// touchButton is an on-screen button triggering an action.
+type touchButton struct {
+	action action
+	label  string
+	x, y   float64
+}
+
+// touchControls is an on-screen joystick, started wherever the left half of
+// the screen is touched, and buttons on the right half.
+type touchControls struct {
+	width, height float64
+	buttons       []touchButton
+
+	// Touch driving the joystick.
+	stick        e.TouchID
+	stickActive  bool
+	baseX, baseY float64
+	knobX, knobY float64
+
+	// Touches that may turn out to be taps, and the number of ticks read to
+	// time them.
+	taps  map[e.TouchID]touchStart
+	ticks int
+
+	// The controls are only drawn once the screen has been touched.
+	used bool
+}
+
+// touchStart is where and when a touch began.
+type touchStart struct {
+	x, y float64
+	tick int
+}
+
+func newTouchControls(width, height int) *touchControls {
+	return &touchControls{
+		width:  float64(width),
+		height: float64(height),
+		taps:   make(map[e.TouchID]touchStart),
+		buttons: []touchButton{
+			{action: actionAttack, label: "A", x: float64(width) - 36, y: float64(height) - 36},
+		},
+	}
+}
+
+func (t *touchControls) read(_ *bindings, in *intent) {
+	t.ticks++
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		t.used = true
+		x, y := touchPosition(id)
+
+		if button, ok := t.buttonAt(x, y); ok {
+			in.pressed[button.action] = true
+			continue
+		}
+		t.taps[id] = touchStart{x: x, y: y, tick: t.ticks}
+		if !t.stickActive && x < t.width/2 {
+			t.stick, t.stickActive = id, true
+			t.baseX, t.baseY = x, y
+			t.knobX, t.knobY = x, y
+		}
+	}
+
+	for id, start := range t.taps {
+		if !inpututil.IsTouchJustReleased(id) {
+			continue
+		}
+		delete(t.taps, id)
+		x, y := inpututil.TouchPositionInPreviousTick(id)
+		if t.ticks-start.tick <= tapTicks && math.Hypot(float64(x)-start.x, float64(y)-start.y) <= tapSlop {
+			in.tap(start.x, start.y)
+		}
+	}
+
+	if !t.stickActive {
+		return
+	}
+	if inpututil.IsTouchJustReleased(t.stick) {
+		t.stickActive = false
+		return
+	}
+
+	x, y := touchPosition(t.stick)
+	dx, dy := x-t.baseX, y-t.baseY
+	if distance := math.Hypot(dx, dy); distance > joystickRadius {
+		dx, dy = dx/distance*joystickRadius, dy/distance*joystickRadius
+	}
+	t.knobX, t.knobY = t.baseX+dx, t.baseY+dy
+
+	mx, my := dx/joystickRadius, dy/joystickRadius
+	if in.moveX == 0 && in.moveY == 0 && math.Hypot(mx, my) > stickDeadZone {
+		in.moveX, in.moveY = mx, my
+	}
+}
+
+func (t *touchControls) buttonAt(x, y float64) (touchButton, bool) {
+	for _, button := range t.buttons {
+		if math.Hypot(x-button.x, y-button.y) <= buttonRadius {
+			return button, true
+		}
+	}
+	return touchButton{}, false
+}
+
+func (t *touchControls) draw(screen *e.Image) {
+	if !t.used {
+		return
+	}
+	if t.stickActive {
+		vector.StrokeCircle(screen, float32(t.baseX), float32(t.baseY), joystickRadius, 2, touchColor, true)
+		vector.DrawFilledCircle(screen, float32(t.knobX), float32(t.knobY), joystickRadius/3, touchActiveColor, true)
+	}
+	for _, button := range t.buttons {
+		vector.DrawFilledCircle(screen, float32(button.x), float32(button.y), buttonRadius, touchColor, true)
+		ebitenutil.DebugPrintAt(screen, button.label, int(button.x)-3, int(button.y)-8)
+	}
+}
+
+func touchPosition(id e.TouchID) (float64, float64) {
+	x, y := e.TouchPosition(id)
+	return float64(x), float64(y)
+}
@@ -0,0 +1,50 @@
+package npc
+
+import (
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+// Horde holds the brains of the monsters in the world. It is not safe for
+// concurrent use, a single goroutine makes the monsters think.
+type Horde struct {
+	monsters map[string]*Monster
+}
+
+func NewHorde() *Horde {
+	return &Horde{monsters: make(map[string]*Monster)}
+}
+
+func (h *Horde) Add(m *Monster) {
+	h.monsters[m.ID] = m
+}
+
+// Len returns the number of monsters.
+func (h *Horde) Len() int {
+	return len(h.monsters)
+}
+
+// Think makes every monster think about a snapshot of the units, and returns
+// the events of all of them. Monsters prey on the living units that are not
+// monsters and can be hurt.
+func (h *Horde) Think(units map[string]*events.Unit, now time.Time) []*events.Event {
+	var prey []*events.Unit
+	for id, unit := range units {
+		if _, isMonster := h.monsters[id]; isMonster {
+			continue
+		}
+		if unit.Action == events.Action_DEAD || unit.Invulnerable {
+			continue
+		}
+		prey = append(prey, unit)
+	}
+
+	var out []*events.Event
+	for id, m := range h.monsters {
+		if self, ok := units[id]; ok {
+			out = append(out, m.Think(self, prey, now)...)
+		}
+	}
+	return out
+}
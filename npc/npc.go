@@ -0,0 +1,330 @@
+// Package npc holds the brains of the monsters. A brain looks at the units
+// around its monster and decides what the monster does, in the same events a
+// client sends for its player, so the server applies and broadcasts them the
+// same way.
+package npc
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
+	w "github.com/patrick-me/game_one/world"
+)
+
+// State is what a monster is busy with.
+type State int
+
+const (
+	Idle State = iota
+	Wander
+	Chase
+	Attack
+	Flee
+)
+
+func (s State) String() string {
+	switch s {
+	case Idle:
+		return "idle"
+	case Wander:
+		return "wander"
+	case Chase:
+		return "chase"
+	case Attack:
+		return "attack"
+	case Flee:
+		return "flee"
+	}
+	return "unknown"
+}
+
+const (
+	// Distance from its home a wandering monster stays within.
+	wanderRadius = 48
+
+	// A monster gives up a chase this many aggro radii away from its home.
+	leashFactor = 3
+
+	// Smallest change of heading worth a new move, as the cosine of the angle.
+	turnThreshold = 0.97
+
+	// Distance at which a waypoint is reached.
+	waypointRadius = 4
+
+	// Distance the target of a chase moves, about a tile, before the monster
+	// looks for a new way to it.
+	replanDistance = 16
+)
+
+// Paths finds the way around the walls, as World.FindPath does.
+type Paths interface {
+	FindPath(fromX, fromY, toX, toY float64) ([]tilemap.Point, bool)
+}
+
+// Monster is the brain of a monster unit.
+type Monster struct {
+	ID    string
+	Kind  w.MonsterKind
+	State State
+
+	// Position the monster spawned at, it wanders around it.
+	homeX, homeY float64
+
+	// ID of the unit the monster chases, attacks or flees.
+	target string
+
+	// End of the current idle or wander.
+	until time.Time
+
+	// Waypoints left on the way to where the monster goes, and the last one
+	// passed, or where the way started.
+	path  []tilemap.Point
+	from  tilemap.Point
+	paths Paths
+
+	// Where the path was planned to.
+	goal tilemap.Point
+
+	lastAttack time.Time
+	rnd        *rand.Rand
+}
+
+// New returns the brain of the monster unit just spawned. Without paths, the
+// monster goes everywhere in a straight line.
+func New(unit *events.Unit, kind w.MonsterKind, paths Paths) *Monster {
+	return &Monster{
+		ID:    unit.ID,
+		Kind:  kind,
+		homeX: unit.X,
+		homeY: unit.Y,
+		paths: paths,
+		rnd:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Think updates the state of the monster from its unit and the prey around,
+// the living player units. It returns the events making the unit do what the
+// monster decided, none when it keeps doing the same.
+func (m *Monster) Think(self *events.Unit, prey []*events.Unit, now time.Time) []*events.Event {
+	if self.Action == events.Action_DEAD {
+		// Start over after respawning.
+		m.State = Idle
+		m.target = ""
+		m.until = time.Time{}
+		m.path = nil
+		return nil
+	}
+
+	target := m.pickTarget(self, prey)
+	if target == nil {
+		m.target = ""
+		return m.roam(self, now)
+	}
+	m.target = target.ID
+
+	dx, dy := target.X-self.X, target.Y-self.Y
+	switch {
+	case float64(self.Hp) < m.Kind.FleeBelow*float64(self.MaxHp):
+		m.State = Flee
+		m.path = nil
+		return m.move(self, -dx, -dy)
+
+	case inReach(self, target):
+		m.State = Attack
+		var out []*events.Event
+		if self.Action == events.Action_RUN {
+			out = append(out, idle(self.ID))
+		}
+		if now.Sub(m.lastAttack) >= m.Kind.AttackPeriod {
+			m.lastAttack = now
+			out = append(out, attack(self.ID, towards(dx, dy)))
+		}
+		return out
+	}
+
+	// Finding a path is costly, the way is only planned again once the
+	// target has moved away from where it led or the monster got there.
+	m.State = Chase
+	if len(m.path) == 0 || math.Hypot(target.X-m.goal.X, target.Y-m.goal.Y) > replanDistance {
+		m.goTo(self, target.X, target.Y)
+	}
+	out, walking := m.follow(self)
+	if !walking {
+		m.goTo(self, target.X, target.Y)
+		out, _ = m.follow(self)
+	}
+	return out
+}
+
+// pickTarget returns the unit the monster goes after: the one it already
+// targets while it stays in sight, or else the nearest prey within its aggro
+// radius. A monster led too far from its home gives up.
+func (m *Monster) pickTarget(self *events.Unit, prey []*events.Unit) *events.Unit {
+	leash := leashFactor * m.Kind.AggroRadius
+	if math.Hypot(self.X-m.homeX, self.Y-m.homeY) > leash {
+		return nil
+	}
+
+	var nearest *events.Unit
+	best := m.Kind.AggroRadius
+	for _, unit := range prey {
+		d := math.Hypot(unit.X-self.X, unit.Y-self.Y)
+		if unit.ID == m.target && d <= leash {
+			return unit
+		}
+		if d <= best {
+			nearest, best = unit, d
+		}
+	}
+	return nearest
+}
+
+// roam alternates between standing idle and wandering around the home of
+// the monster.
+func (m *Monster) roam(self *events.Unit, now time.Time) []*events.Event {
+	switch m.State {
+	case Idle:
+		if now.Before(m.until) {
+			return nil
+		}
+	case Wander:
+		if now.Before(m.until) {
+			if out, walking := m.follow(self); walking {
+				return out
+			}
+		}
+		return m.rest(self, now)
+	}
+	if self.Speed <= 0 {
+		return m.rest(self, now)
+	}
+
+	// Walk to a random spot around home, which also brings the monster back
+	// after a fight.
+	angle := m.rnd.Float64() * 2 * math.Pi
+	radius := m.rnd.Float64() * wanderRadius
+	m.goTo(self, m.homeX+math.Cos(angle)*radius, m.homeY+math.Sin(angle)*radius)
+
+	// Give up if stuck on the way.
+	length, x, y := 0.0, self.X, self.Y
+	for _, p := range m.path {
+		length += math.Hypot(p.X-x, p.Y-y)
+		x, y = p.X, p.Y
+	}
+	m.State = Wander
+	m.until = now.Add(time.Second + time.Duration(2*length/self.Speed*float64(time.Second)))
+	out, _ := m.follow(self)
+	return out
+}
+
+// rest stops the monster for a while.
+func (m *Monster) rest(self *events.Unit, now time.Time) []*events.Event {
+	m.State = Idle
+	m.until = now.Add(randDuration(m.rnd, time.Second, 4*time.Second))
+	m.path = nil
+	if self.Action != events.Action_RUN {
+		return nil
+	}
+	return []*events.Event{idle(self.ID)}
+}
+
+// goTo sets the path of the monster to x, y, a straight line when there is
+// no way around the walls.
+func (m *Monster) goTo(self *events.Unit, x, y float64) {
+	m.from = tilemap.Point{X: self.X, Y: self.Y}
+	m.goal = tilemap.Point{X: x, Y: y}
+	if m.paths != nil {
+		if path, ok := m.paths.FindPath(self.X, self.Y, x, y); ok {
+			m.path = path
+			return
+		}
+	}
+	m.path = []tilemap.Point{{X: x, Y: y}}
+}
+
+// follow makes the unit run to the next waypoint of the path. It reports
+// false once the monster has arrived.
+func (m *Monster) follow(self *events.Unit) ([]*events.Event, bool) {
+	for len(m.path) > 0 {
+		next := m.path[0]
+		dx, dy := next.X-self.X, next.Y-self.Y
+		// The unit may run past a waypoint between two thoughts.
+		passed := (next.X-m.from.X)*dx+(next.Y-m.from.Y)*dy <= 0
+		if math.Hypot(dx, dy) > waypointRadius && !passed {
+			return m.move(self, dx, dy), true
+		}
+		m.from = next
+		m.path = m.path[1:]
+	}
+	return nil, false
+}
+
+// move makes the unit run towards dx, dy, unless it already runs about that
+// way.
+func (m *Monster) move(self *events.Unit, dx, dy float64) []*events.Event {
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return nil
+	}
+	vx, vy := dx/length, dy/length
+	if self.Action == events.Action_RUN && self.Vx*vx+self.Vy*vy > turnThreshold {
+		return nil
+	}
+	return []*events.Event{{
+		Type: events.Event_MOVE,
+		Data: &events.Event_Move{
+			Move: &events.EventMove{
+				UnitID:    self.ID,
+				Direction: w.Facing(vx, vy, self.Direction),
+				Vx:        vx,
+				Vy:        vy,
+			},
+		},
+	}}
+}
+
+// inReach reports whether the unit hits the target when attacking it.
+func inReach(self, target *events.Unit) bool {
+	dx, dy := target.X-self.X, target.Y-self.Y
+	facing := &events.Unit{X: self.X, Y: self.Y, Direction: towards(dx, dy)}
+	return w.AttackBox(facing).Intersects(w.Hitbox(target))
+}
+
+// towards returns the direction closest to dx, dy.
+func towards(dx, dy float64) events.Direction {
+	if math.Abs(dx) >= math.Abs(dy) {
+		if dx < 0 {
+			return events.Direction_LEFT
+		}
+		return events.Direction_RIGHT
+	}
+	if dy < 0 {
+		return events.Direction_UP
+	}
+	return events.Direction_DOWN
+}
+
+func idle(id string) *events.Event {
+	return &events.Event{
+		Type: events.Event_IDLE,
+		Data: &events.Event_Idle{
+			Idle: &events.EventIdle{UnitID: id},
+		},
+	}
+}
+
+func attack(id string, direction events.Direction) *events.Event {
+	return &events.Event{
+		Type: events.Event_ATTACK,
+		Data: &events.Event_Attack{
+			Attack: &events.EventAttack{UnitID: id, Direction: direction},
+		},
+	}
+}
+
+func randDuration(rnd *rand.Rand, lo, hi time.Duration) time.Duration {
+	return lo + time.Duration(rnd.Int63n(int64(hi-lo)))
+}
@@ -0,0 +1,58 @@
+package npc
+
+import (
+	"testing"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
+	w "github.com/patrick-me/game_one/world"
+)
+
+// countingPaths finds straight paths and counts the searches.
+type countingPaths struct {
+	searches int
+}
+
+func (p *countingPaths) FindPath(fromX, fromY, toX, toY float64) ([]tilemap.Point, bool) {
+	p.searches++
+	return []tilemap.Point{{X: toX, Y: toY}}, true
+}
+
+func TestChaseReplansOnlyWhenTargetMoves(t *testing.T) {
+	paths := &countingPaths{}
+	self := &events.Unit{ID: "m", Action: events.Action_IDLE, Speed: 60, Hp: 40, MaxHp: 40}
+	m := New(self, w.MonsterKinds["goblin"], paths)
+	prey := &events.Unit{ID: "p", X: 60, Action: events.Action_IDLE}
+
+	now := time.Now()
+	think := func() {
+		now = now.Add(100 * time.Millisecond)
+		if out := m.Think(self, []*events.Unit{prey}, now); len(out) > 0 {
+			if move := out[0].GetMove(); move != nil {
+				self.Action, self.Vx, self.Vy = events.Action_RUN, move.Vx, move.Vy
+			}
+		}
+		self.X += self.Vx
+		self.Y += self.Vy
+	}
+
+	for i := 0; i < 5; i++ {
+		think()
+	}
+	if m.State != Chase || paths.searches != 1 {
+		t.Fatalf("state %v after %d searches, want a chase planned once", m.State, paths.searches)
+	}
+
+	prey.X += replanDistance / 2
+	think()
+	if paths.searches != 1 {
+		t.Errorf("%d searches after the target moved a little, want 1", paths.searches)
+	}
+
+	prey.X += replanDistance
+	think()
+	if paths.searches != 2 {
+		t.Errorf("%d searches after the target moved away, want 2", paths.searches)
+	}
+}
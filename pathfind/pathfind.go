@@ -0,0 +1,246 @@
+// Package pathfind finds paths across the walkable tiles of a map with A*.
+// Paths may go diagonally, without cutting the corners of blocked tiles, and
+// are smoothed into the fewest straight lines a unit can walk along.
+package pathfind
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/patrick-me/game_one/tilemap"
+)
+
+// Grid is a grid of tiles a unit walks on or not.
+type Grid interface {
+	// Walkable reports whether the tile at x, y is inside the grid and not
+	// blocked.
+	Walkable(x, y int) bool
+}
+
+// Finder finds paths on a grid. Positions are in pixels, at the center of
+// the box of the unit walking along the path.
+type Finder struct {
+	Grid          Grid
+	Width, Height int
+
+	// Size of the tiles in pixels.
+	TileWidth, TileHeight float64
+
+	// Size of the box walking along the paths. Smoothing never takes it over
+	// a blocked tile.
+	UnitWidth, UnitHeight float64
+}
+
+// ForMap returns a finder for units of the given size on the map.
+func ForMap(m *tilemap.Map, unitWidth, unitHeight float64) *Finder {
+	return &Finder{
+		Grid:       m,
+		Width:      m.Width,
+		Height:     m.Height,
+		TileWidth:  float64(m.TileWidth),
+		TileHeight: float64(m.TileHeight),
+		UnitWidth:  unitWidth,
+		UnitHeight: unitHeight,
+	}
+}
+
+// Moves to the neighbours of a tile, straight ones first.
+var neighbours = [8][2]int{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// Find returns the waypoints leading from one position to the other, the
+// last one being the destination. It reports false when the destination
+// can't be reached.
+func (f *Finder) Find(fromX, fromY, toX, toY float64) ([]tilemap.Point, bool) {
+	start, ok := f.tile(fromX, fromY)
+	if !ok {
+		return nil, false
+	}
+	goal, ok := f.tile(toX, toY)
+	if !ok || !f.Grid.Walkable(goal%f.Width, goal/f.Width) {
+		return nil, false
+	}
+
+	tiles, ok := f.search(start, goal)
+	if !ok {
+		return nil, false
+	}
+
+	// A destination too close to a wall is replaced by the middle of its
+	// tile, where the unit fits.
+	if !f.fits(toX, toY) {
+		toX, toY = f.center(goal)
+	}
+	// Walk through the middle of the tiles after the start one, then to the
+	// destination itself.
+	points := make([]tilemap.Point, 0, len(tiles))
+	for i := 1; i < len(tiles)-1; i++ {
+		x, y := f.center(tiles[i])
+		points = append(points, tilemap.Point{X: x, Y: y})
+	}
+	points = append(points, tilemap.Point{X: toX, Y: toY})
+	return f.smooth(tilemap.Point{X: fromX, Y: fromY}, points), true
+}
+
+// tile returns the index of the tile at the position.
+func (f *Finder) tile(x, y float64) (int, bool) {
+	tx := int(math.Floor(x / f.TileWidth))
+	ty := int(math.Floor(y / f.TileHeight))
+	if tx < 0 || ty < 0 || tx >= f.Width || ty >= f.Height {
+		return 0, false
+	}
+	return ty*f.Width + tx, true
+}
+
+// center returns the middle of the tile.
+func (f *Finder) center(t int) (float64, float64) {
+	return (float64(t%f.Width) + 0.5) * f.TileWidth, (float64(t/f.Width) + 0.5) * f.TileHeight
+}
+
+// search runs A* from tile to tile and returns the tiles of the path, both
+// ends included. The start tile may be blocked, a unit pushed into a wall
+// can still walk out.
+func (f *Finder) search(start, goal int) ([]int, bool) {
+	n := f.Width * f.Height
+	cost := make([]float64, n)
+	from := make([]int, n)
+	closed := make([]bool, n)
+	for i := range cost {
+		cost[i] = math.Inf(1)
+	}
+
+	gx, gy := goal%f.Width, goal/f.Width
+	open := &queue{}
+	cost[start] = 0
+	from[start] = -1
+	heap.Push(open, node{tile: start, score: heuristic(start%f.Width, start/f.Width, gx, gy)})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(node).tile
+		if current == goal {
+			var path []int
+			for t := goal; t != -1; t = from[t] {
+				path = append(path, t)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, true
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		x, y := current%f.Width, current/f.Width
+		for _, d := range neighbours {
+			nx, ny := x+d[0], y+d[1]
+			if !f.Grid.Walkable(nx, ny) {
+				continue
+			}
+			step := 1.0
+			if d[0] != 0 && d[1] != 0 {
+				// Going diagonally needs both tiles along the corner free.
+				if !f.Grid.Walkable(x+d[0], y) || !f.Grid.Walkable(x, y+d[1]) {
+					continue
+				}
+				step = math.Sqrt2
+			}
+
+			next := ny*f.Width + nx
+			if closed[next] || cost[current]+step >= cost[next] {
+				continue
+			}
+			cost[next] = cost[current] + step
+			from[next] = current
+			heap.Push(open, node{tile: next, cost: cost[next], score: cost[next] + heuristic(nx, ny, gx, gy)})
+		}
+	}
+	return nil, false
+}
+
+// heuristic is the octile distance between two tiles, the length of the
+// shortest path on an empty grid.
+func heuristic(x0, y0, x1, y1 int) float64 {
+	dx := math.Abs(float64(x1 - x0))
+	dy := math.Abs(float64(y1 - y0))
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
+
+// smooth drops the waypoints the unit can walk past in a straight line, going
+// as far as possible from every waypoint kept.
+func (f *Finder) smooth(from tilemap.Point, points []tilemap.Point) []tilemap.Point {
+	var smoothed []tilemap.Point
+	for i, p := range points {
+		if i == len(points)-1 || !f.clear(from, points[i+1]) {
+			smoothed = append(smoothed, p)
+			from = p
+		}
+	}
+	return smoothed
+}
+
+// clear reports whether the unit walks from one point to the other without
+// touching a blocked tile.
+func (f *Finder) clear(a, b tilemap.Point) bool {
+	step := math.Min(f.TileWidth, f.TileHeight) / 4
+	steps := int(math.Ceil(math.Hypot(b.X-a.X, b.Y-a.Y) / step))
+	for i := 0; i <= steps; i++ {
+		t := 1.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		if !f.fits(a.X+(b.X-a.X)*t, a.Y+(b.Y-a.Y)*t) {
+			return false
+		}
+	}
+	return true
+}
+
+// fits reports whether the box of the unit centered on x, y only overlaps
+// walkable tiles.
+func (f *Finder) fits(x, y float64) bool {
+	x0 := int(math.Floor((x - f.UnitWidth/2) / f.TileWidth))
+	y0 := int(math.Floor((y - f.UnitHeight/2) / f.TileHeight))
+	x1 := int(math.Ceil((x+f.UnitWidth/2)/f.TileWidth)) - 1
+	y1 := int(math.Ceil((y+f.UnitHeight/2)/f.TileHeight)) - 1
+	for ty := y0; ty <= y1; ty++ {
+		for tx := x0; tx <= x1; tx++ {
+			if !f.Grid.Walkable(tx, ty) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type node struct {
+	tile int
+
+	// Length of the path to the tile, and its estimated length through it.
+	cost, score float64
+}
+
+// queue is a priority queue of the tiles to visit, lowest score first. Among
+// tiles scored the same, the farthest from the start is visited first, which
+// is the nearest to the goal.
+type queue []node
+
+func (q queue) Len() int { return len(q) }
+func (q queue) Less(i, j int) bool {
+	if q[i].score == q[j].score {
+		return q[i].cost > q[j].cost
+	}
+	return q[i].score < q[j].score
+}
+func (q queue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x any)   { *q = append(*q, x.(node)) }
+
+func (q *queue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,220 @@
+package pathfind
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/patrick-me/game_one/tilemap"
+)
+
+const tileSize = 16
+
+// grid is a grid of tiles, blocked ones drawn as '#'.
+type grid struct {
+	width, height int
+	blocked       []bool
+}
+
+func parseGrid(rows ...string) *grid {
+	g := &grid{width: len(rows[0]), height: len(rows)}
+	for _, row := range rows {
+		for _, c := range row {
+			g.blocked = append(g.blocked, c == '#')
+		}
+	}
+	return g
+}
+
+func (g *grid) Walkable(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.width && y < g.height && !g.blocked[y*g.width+x]
+}
+
+func (g *grid) finder() *Finder {
+	return &Finder{
+		Grid:       g,
+		Width:      g.width,
+		Height:     g.height,
+		TileWidth:  tileSize,
+		TileHeight: tileSize,
+		UnitWidth:  8,
+		UnitHeight: 8,
+	}
+}
+
+// at returns the middle of the tile at x, y.
+func at(x, y int) tilemap.Point {
+	return tilemap.Point{X: (float64(x) + 0.5) * tileSize, Y: (float64(y) + 0.5) * tileSize}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     []string
+		from, to tilemap.Point
+		want     []tilemap.Point
+		ok       bool
+	}{
+		{
+			name: "straight line",
+			rows: []string{
+				".....",
+				".....",
+			},
+			from: at(0, 0),
+			to:   at(4, 1),
+			want: []tilemap.Point{at(4, 1)},
+			ok:   true,
+		},
+		{
+			name: "smoothed around a wall",
+			rows: []string{
+				".....",
+				"####.",
+				".....",
+			},
+			from: at(0, 0),
+			to:   at(0, 2),
+			want: []tilemap.Point{at(4, 0), at(4, 2), at(0, 2)},
+			ok:   true,
+		},
+		{
+			name: "no corner cutting",
+			rows: []string{
+				"..",
+				"#.",
+			},
+			from: at(0, 0),
+			to:   at(1, 1),
+			want: []tilemap.Point{at(1, 0), at(1, 1)},
+			ok:   true,
+		},
+		{
+			name: "no squeezing between diagonal walls",
+			rows: []string{
+				".#",
+				"#.",
+			},
+			from: at(0, 0),
+			to:   at(1, 1),
+		},
+		{
+			name: "start inside a wall",
+			rows: []string{
+				"#..",
+				"...",
+			},
+			from: at(0, 0),
+			to:   at(2, 0),
+			// Out of the wall first, the straight line crosses it.
+			want: []tilemap.Point{at(1, 0), at(2, 0)},
+			ok:   true,
+		},
+		{
+			name: "walled in goal",
+			rows: []string{
+				"...#.",
+				"...##",
+			},
+			from: at(0, 0),
+			to:   at(4, 0),
+		},
+		{
+			name: "goal inside a wall",
+			rows: []string{
+				"..#",
+			},
+			from: at(0, 0),
+			to:   at(2, 0),
+		},
+		{
+			name: "goal off the grid",
+			rows: []string{
+				"...",
+			},
+			from: at(0, 0),
+			to:   at(3, 0),
+		},
+		{
+			name: "goal too close to a wall",
+			rows: []string{
+				"..#",
+			},
+			from: at(0, 0),
+			to:   tilemap.Point{X: 2*tileSize - 1, Y: tileSize / 2},
+			want: []tilemap.Point{at(1, 0)},
+			ok:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parseGrid(tt.rows...).finder()
+			got, ok := f.Find(tt.from.X, tt.from.Y, tt.to.X, tt.to.Y)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// The unit walks every straight line of a path, from the start on, without
+// touching a wall.
+func TestFindPathsAreClear(t *testing.T) {
+	g := randomGrid(64, 64, 0.3, 2)
+	f := g.finder()
+	rnd := rand.New(rand.NewSource(3))
+	found := 0
+	for i := 0; i < 200; i++ {
+		from := at(rnd.Intn(g.width), rnd.Intn(g.height))
+		to := at(rnd.Intn(g.width), rnd.Intn(g.height))
+		if !f.fits(from.X, from.Y) {
+			continue
+		}
+		path, ok := f.Find(from.X, from.Y, to.X, to.Y)
+		if !ok {
+			continue
+		}
+		found++
+		if path[len(path)-1] != to {
+			t.Errorf("path from %v ends at %v, want %v", from, path[len(path)-1], to)
+		}
+		for _, p := range path {
+			if !f.clear(from, p) {
+				t.Fatalf("path %v goes from %v to %v through a wall", path, from, p)
+			}
+			from = p
+		}
+	}
+	if found == 0 {
+		t.Fatal("no path found")
+	}
+}
+
+// randomGrid returns a grid with the given share of its tiles blocked at
+// random, the corners free.
+func randomGrid(width, height int, walls float64, seed int64) *grid {
+	rnd := rand.New(rand.NewSource(seed))
+	g := &grid{width: width, height: height, blocked: make([]bool, width*height)}
+	for i := range g.blocked {
+		g.blocked[i] = rnd.Float64() < walls
+	}
+	for _, i := range []int{0, width - 1, (height - 1) * width, width*height - 1} {
+		g.blocked[i] = false
+	}
+	return g
+}
+
+func BenchmarkFind(b *testing.B) {
+	g := randomGrid(512, 512, 0.25, 1)
+	f := g.finder()
+	from, to := at(0, 0), at(g.width-1, g.height-1)
+	if _, ok := f.Find(from.X, from.Y, to.X, to.Y); !ok {
+		b.Fatal("no path across the grid")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Find(from.X, from.Y, to.X, to.Y)
+	}
+}
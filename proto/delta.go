@@ -0,0 +1,172 @@
+package events
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Diff returns the deltas turning the base units into the current ones and the
+// IDs of the units that are gone. A nil base produces a full snapshot.
+func Diff(base, current map[string]*Unit) ([]*UnitDelta, []string) {
+	var deltas []*UnitDelta
+	for id, unit := range current {
+		if delta := diffUnit(base[id], unit); delta != nil {
+			deltas = append(deltas, delta)
+		}
+	}
+
+	var removed []string
+	for id := range base {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	return deltas, removed
+}
+
+// diffUnit returns nil when the unit has not changed.
+func diffUnit(base, unit *Unit) *UnitDelta {
+	if base == nil {
+		// A new unit carries every field, zero values included.
+		return &UnitDelta{
+			ID:           unit.ID,
+			X:            proto.Float64(unit.X),
+			Y:            proto.Float64(unit.Y),
+			SpriteName:   proto.String(unit.SpriteName),
+			Action:       unit.Action.Enum(),
+			Frame:        proto.Int32(unit.Frame),
+			Direction:    unit.Direction.Enum(),
+			Speed:        proto.Float64(unit.Speed),
+			LastInputSeq: proto.Uint32(unit.LastInputSeq),
+			Vx:           proto.Float64(unit.Vx),
+			Vy:           proto.Float64(unit.Vy),
+			Hp:           proto.Int32(unit.Hp),
+			MaxHp:        proto.Int32(unit.MaxHp),
+			Invulnerable: proto.Bool(unit.Invulnerable),
+		}
+	}
+
+	delta := &UnitDelta{ID: unit.ID}
+	changed := false
+	if unit.X != base.X {
+		delta.X, changed = proto.Float64(unit.X), true
+	}
+	if unit.Y != base.Y {
+		delta.Y, changed = proto.Float64(unit.Y), true
+	}
+	if unit.SpriteName != base.SpriteName {
+		delta.SpriteName, changed = proto.String(unit.SpriteName), true
+	}
+	if unit.Action != base.Action {
+		delta.Action, changed = unit.Action.Enum(), true
+	}
+	if unit.Frame != base.Frame {
+		delta.Frame, changed = proto.Int32(unit.Frame), true
+	}
+	if unit.Direction != base.Direction {
+		delta.Direction, changed = unit.Direction.Enum(), true
+	}
+	if unit.Speed != base.Speed {
+		delta.Speed, changed = proto.Float64(unit.Speed), true
+	}
+	if unit.LastInputSeq != base.LastInputSeq {
+		delta.LastInputSeq, changed = proto.Uint32(unit.LastInputSeq), true
+	}
+	if unit.Vx != base.Vx {
+		delta.Vx, changed = proto.Float64(unit.Vx), true
+	}
+	if unit.Vy != base.Vy {
+		delta.Vy, changed = proto.Float64(unit.Vy), true
+	}
+	if unit.Hp != base.Hp {
+		delta.Hp, changed = proto.Int32(unit.Hp), true
+	}
+	if unit.MaxHp != base.MaxHp {
+		delta.MaxHp, changed = proto.Int32(unit.MaxHp), true
+	}
+	if unit.Invulnerable != base.Invulnerable {
+		delta.Invulnerable, changed = proto.Bool(unit.Invulnerable), true
+	}
+	if !changed {
+		return nil
+	}
+	return delta
+}
+
+// Patch applies deltas and removals to a copy of the base units.
+func Patch(base map[string]*Unit, deltas []*UnitDelta, removed []string) map[string]*Unit {
+	units := make(map[string]*Unit, len(base)+len(deltas))
+	for id, unit := range base {
+		units[id] = unit
+	}
+	for _, id := range removed {
+		delete(units, id)
+	}
+
+	for _, delta := range deltas {
+		var unit *Unit
+		if prev, ok := units[delta.ID]; ok {
+			unit = proto.Clone(prev).(*Unit)
+		} else {
+			unit = &Unit{ID: delta.ID}
+		}
+		patchUnit(unit, delta)
+		units[delta.ID] = unit
+	}
+	return units
+}
+
+func patchUnit(unit *Unit, delta *UnitDelta) {
+	if delta.X != nil {
+		unit.X = *delta.X
+	}
+	if delta.Y != nil {
+		unit.Y = *delta.Y
+	}
+	if delta.SpriteName != nil {
+		unit.SpriteName = *delta.SpriteName
+	}
+	if delta.Action != nil {
+		unit.Action = *delta.Action
+	}
+	if delta.Frame != nil {
+		unit.Frame = *delta.Frame
+	}
+	if delta.Direction != nil {
+		unit.Direction = *delta.Direction
+	}
+	if delta.Speed != nil {
+		unit.Speed = *delta.Speed
+	}
+	if delta.LastInputSeq != nil {
+		unit.LastInputSeq = *delta.LastInputSeq
+	}
+	if delta.Vx != nil {
+		unit.Vx = *delta.Vx
+	}
+	if delta.Vy != nil {
+		unit.Vy = *delta.Vy
+	}
+	if delta.Hp != nil {
+		unit.Hp = *delta.Hp
+	}
+	if delta.MaxHp != nil {
+		unit.MaxHp = *delta.MaxHp
+	}
+	if delta.Invulnerable != nil {
+		unit.Invulnerable = *delta.Invulnerable
+	}
+}
+
+// TrimHistory deletes the oldest unit states from a history by tick until it
+// holds at most limit of them. The ticks don't have to follow each other.
+func TrimHistory(history map[uint64]map[string]*Unit, limit int) {
+	for len(history) > limit {
+		oldest := uint64(math.MaxUint64)
+		for tick := range history {
+			oldest = min(oldest, tick)
+		}
+		delete(history, oldest)
+	}
+}
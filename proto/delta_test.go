@@ -0,0 +1,112 @@
+package events
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// sameUnits reports whether both maps hold equal units under the same IDs.
+func sameUnits(a, b map[string]*Unit) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, unit := range a {
+		if !proto.Equal(unit, b[id]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDiffPatchRoundTrip(t *testing.T) {
+	base := map[string]*Unit{
+		"still":   {ID: "still", X: 1, Y: 2, SpriteName: "elf_f", Speed: 60, Hp: 100, MaxHp: 100},
+		"moving":  {ID: "moving", X: 10, Y: 20, Action: Action_RUN, Vx: 1, Hp: 100, MaxHp: 100},
+		"leaving": {ID: "leaving", X: 5, Y: 5},
+	}
+	current := map[string]*Unit{
+		"still":  {ID: "still", X: 1, Y: 2, SpriteName: "elf_f", Speed: 60, Hp: 100, MaxHp: 100},
+		"moving": {ID: "moving", X: 11, Y: 20, Action: Action_IDLE, Direction: Direction_LEFT, Hp: 80, MaxHp: 100, LastInputSeq: 7},
+		"new":    {ID: "new", X: 30, Y: 40, SpriteName: "goblin", Action: Action_RUN, Invulnerable: true},
+	}
+
+	deltas, removed := Diff(base, current)
+	if len(deltas) != 2 {
+		t.Errorf("got %d deltas, want 2 for the changed and the new unit", len(deltas))
+	}
+	if len(removed) != 1 || removed[0] != "leaving" {
+		t.Errorf("removed = %v, want [leaving]", removed)
+	}
+
+	// Over the wire, as the server sends it.
+	data, err := proto.Marshal(&EventSnapshot{Tick: 2, BaseTick: 1, Units: deltas, Removed: removed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := &EventSnapshot{}
+	if err := proto.Unmarshal(data, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	got := Patch(base, snapshot.Units, snapshot.Removed)
+	if !sameUnits(got, current) {
+		t.Errorf("Patch(base, Diff(base, current)) = %v, want %v", got, current)
+	}
+	if base["moving"].X != 10 || len(base) != 3 {
+		t.Error("Patch changed the base units")
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	units := map[string]*Unit{"a": {ID: "a", X: 1}}
+	deltas, removed := Diff(units, map[string]*Unit{"a": {ID: "a", X: 1}})
+	if len(deltas) != 0 || len(removed) != 0 {
+		t.Errorf("Diff of equal units = %v, %v, want nothing", deltas, removed)
+	}
+}
+
+func TestDiffNewUnitCarriesEveryField(t *testing.T) {
+	// Every field is the zero value, and must still be sent.
+	deltas, _ := Diff(nil, map[string]*Unit{"a": {ID: "a"}})
+	if len(deltas) != 1 {
+		t.Fatalf("got %d deltas, want 1", len(deltas))
+	}
+
+	delta := deltas[0].ProtoReflect()
+	fields := delta.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if field := fields.Get(i); field.HasPresence() && !delta.Has(field) {
+			t.Errorf("new unit delta misses %s", field.Name())
+		}
+	}
+
+	// A unit already known with other values gets the zero values too.
+	known := map[string]*Unit{"a": {ID: "a", X: 5, Action: Action_RUN, Hp: 10, Invulnerable: true}}
+	got := Patch(known, deltas, []string{"a"})
+	if !proto.Equal(got["a"], &Unit{ID: "a"}) {
+		t.Errorf("patched unit = %v, want every field zero", got["a"])
+	}
+}
+
+func TestTrimHistory(t *testing.T) {
+	history := map[uint64]map[string]*Unit{}
+	for _, tick := range []uint64{3, 9, 4, 12, 6} {
+		history[tick] = map[string]*Unit{}
+	}
+
+	TrimHistory(history, 3)
+	if len(history) != 3 {
+		t.Fatalf("history holds %d ticks, want 3", len(history))
+	}
+	for _, tick := range []uint64{6, 9, 12} {
+		if _, ok := history[tick]; !ok {
+			t.Errorf("tick %d was trimmed, want the newest ones kept", tick)
+		}
+	}
+
+	TrimHistory(history, 5)
+	if len(history) != 3 {
+		t.Errorf("history holds %d ticks after trimming to more, want 3", len(history))
+	}
+}
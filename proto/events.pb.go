@@ -77,6 +77,7 @@ type Action int32
 const (
 	Action_RUN  Action = 0
 	Action_IDLE Action = 1
+	Action_DEAD Action = 2
 )
 
 // Enum value maps for Action.
@@ -84,10 +85,12 @@ var (
 	Action_name = map[int32]string{
 		0: "RUN",
 		1: "IDLE",
+		2: "DEAD",
 	}
 	Action_value = map[string]int32{
 		"RUN":  0,
 		"IDLE": 1,
+		"DEAD": 2,
 	}
 )
 
@@ -126,16 +129,34 @@ const (
 	Event_INIT       Event_Type = 2
 	Event_MOVE       Event_Type = 3
 	Event_IDLE       Event_Type = 4
+	Event_SNAPSHOT   Event_Type = 6
+	Event_ACK        Event_Type = 7
+	Event_ENTER_VIEW Event_Type = 8
+	Event_LEAVE_VIEW Event_Type = 9
+	Event_SHUTDOWN   Event_Type = 10
+	Event_ATTACK     Event_Type = 11
+	Event_DAMAGE     Event_Type = 12
+	Event_DEATH      Event_Type = 13
+	Event_RESPAWN    Event_Type = 14
 )
 
 // Enum value maps for Event_Type.
 var (
 	Event_Type_name = map[int32]string{
-		0: "CONNECT",
-		1: "DISCONNECT",
-		2: "INIT",
-		3: "MOVE",
-		4: "IDLE",
+		0:  "CONNECT",
+		1:  "DISCONNECT",
+		2:  "INIT",
+		3:  "MOVE",
+		4:  "IDLE",
+		6:  "SNAPSHOT",
+		7:  "ACK",
+		8:  "ENTER_VIEW",
+		9:  "LEAVE_VIEW",
+		10: "SHUTDOWN",
+		11: "ATTACK",
+		12: "DAMAGE",
+		13: "DEATH",
+		14: "RESPAWN",
 	}
 	Event_Type_value = map[string]int32{
 		"CONNECT":    0,
@@ -143,6 +164,15 @@ var (
 		"INIT":       2,
 		"MOVE":       3,
 		"IDLE":       4,
+		"SNAPSHOT":   6,
+		"ACK":        7,
+		"ENTER_VIEW": 8,
+		"LEAVE_VIEW": 9,
+		"SHUTDOWN":   10,
+		"ATTACK":     11,
+		"DAMAGE":     12,
+		"DEATH":      13,
+		"RESPAWN":    14,
 	}
 )
 
@@ -186,6 +216,15 @@ type Event struct {
 	//	*Event_Init
 	//	*Event_Move
 	//	*Event_Idle
+	//	*Event_Snapshot
+	//	*Event_Ack
+	//	*Event_EnterView
+	//	*Event_LeaveView
+	//	*Event_Shutdown
+	//	*Event_Attack
+	//	*Event_Damage
+	//	*Event_Death
+	//	*Event_Respawn
 	Data isEvent_Data `protobuf_oneof:"data"`
 }
 
@@ -270,6 +309,69 @@ func (x *Event) GetIdle() *EventIdle {
 	return nil
 }
 
+func (x *Event) GetSnapshot() *EventSnapshot {
+	if x, ok := x.GetData().(*Event_Snapshot); ok {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *Event) GetAck() *EventAck {
+	if x, ok := x.GetData().(*Event_Ack); ok {
+		return x.Ack
+	}
+	return nil
+}
+
+func (x *Event) GetEnterView() *EventEnterView {
+	if x, ok := x.GetData().(*Event_EnterView); ok {
+		return x.EnterView
+	}
+	return nil
+}
+
+func (x *Event) GetLeaveView() *EventLeaveView {
+	if x, ok := x.GetData().(*Event_LeaveView); ok {
+		return x.LeaveView
+	}
+	return nil
+}
+
+func (x *Event) GetShutdown() *EventShutdown {
+	if x, ok := x.GetData().(*Event_Shutdown); ok {
+		return x.Shutdown
+	}
+	return nil
+}
+
+func (x *Event) GetAttack() *EventAttack {
+	if x, ok := x.GetData().(*Event_Attack); ok {
+		return x.Attack
+	}
+	return nil
+}
+
+func (x *Event) GetDamage() *EventDamage {
+	if x, ok := x.GetData().(*Event_Damage); ok {
+		return x.Damage
+	}
+	return nil
+}
+
+func (x *Event) GetDeath() *EventDeath {
+	if x, ok := x.GetData().(*Event_Death); ok {
+		return x.Death
+	}
+	return nil
+}
+
+func (x *Event) GetRespawn() *EventRespawn {
+	if x, ok := x.GetData().(*Event_Respawn); ok {
+		return x.Respawn
+	}
+	return nil
+}
+
 type isEvent_Data interface {
 	isEvent_Data()
 }
@@ -294,6 +396,42 @@ type Event_Idle struct {
 	Idle *EventIdle `protobuf:"bytes,6,opt,name=idle,proto3,oneof"`
 }
 
+type Event_Snapshot struct {
+	Snapshot *EventSnapshot `protobuf:"bytes,8,opt,name=snapshot,proto3,oneof"`
+}
+
+type Event_Ack struct {
+	Ack *EventAck `protobuf:"bytes,9,opt,name=ack,proto3,oneof"`
+}
+
+type Event_EnterView struct {
+	EnterView *EventEnterView `protobuf:"bytes,10,opt,name=enterView,proto3,oneof"`
+}
+
+type Event_LeaveView struct {
+	LeaveView *EventLeaveView `protobuf:"bytes,11,opt,name=leaveView,proto3,oneof"`
+}
+
+type Event_Shutdown struct {
+	Shutdown *EventShutdown `protobuf:"bytes,12,opt,name=shutdown,proto3,oneof"`
+}
+
+type Event_Attack struct {
+	Attack *EventAttack `protobuf:"bytes,13,opt,name=attack,proto3,oneof"`
+}
+
+type Event_Damage struct {
+	Damage *EventDamage `protobuf:"bytes,14,opt,name=damage,proto3,oneof"`
+}
+
+type Event_Death struct {
+	Death *EventDeath `protobuf:"bytes,15,opt,name=death,proto3,oneof"`
+}
+
+type Event_Respawn struct {
+	Respawn *EventRespawn `protobuf:"bytes,16,opt,name=respawn,proto3,oneof"`
+}
+
 func (*Event_Connect) isEvent_Data() {}
 
 func (*Event_Disconnect) isEvent_Data() {}
@@ -304,6 +442,24 @@ func (*Event_Move) isEvent_Data() {}
 
 func (*Event_Idle) isEvent_Data() {}
 
+func (*Event_Snapshot) isEvent_Data() {}
+
+func (*Event_Ack) isEvent_Data() {}
+
+func (*Event_EnterView) isEvent_Data() {}
+
+func (*Event_LeaveView) isEvent_Data() {}
+
+func (*Event_Shutdown) isEvent_Data() {}
+
+func (*Event_Attack) isEvent_Data() {}
+
+func (*Event_Damage) isEvent_Data() {}
+
+func (*Event_Death) isEvent_Data() {}
+
+func (*Event_Respawn) isEvent_Data() {}
+
 type EventConnect struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -453,6 +609,8 @@ func (x *EventInit) GetUnits() map[string]*Unit {
 	return nil
 }
 
+// EventMove sets the velocity of a unit as a vector of length up to 1, which
+// is scaled by the unit speed. Direction is the way the unit faces.
 type EventMove struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -460,6 +618,9 @@ type EventMove struct {
 
 	UnitID    string    `protobuf:"bytes,1,opt,name=unitID,proto3" json:"unitID,omitempty"`
 	Direction Direction `protobuf:"varint,2,opt,name=direction,proto3,enum=events.Direction" json:"direction,omitempty"`
+	Seq       uint32    `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	Vx        float64   `protobuf:"fixed64,4,opt,name=vx,proto3" json:"vx,omitempty"`
+	Vy        float64   `protobuf:"fixed64,5,opt,name=vy,proto3" json:"vy,omitempty"`
 }
 
 func (x *EventMove) Reset() {
@@ -508,12 +669,34 @@ func (x *EventMove) GetDirection() Direction {
 	return Direction_LEFT
 }
 
+func (x *EventMove) GetSeq() uint32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *EventMove) GetVx() float64 {
+	if x != nil {
+		return x.Vx
+	}
+	return 0
+}
+
+func (x *EventMove) GetVy() float64 {
+	if x != nil {
+		return x.Vy
+	}
+	return 0
+}
+
 type EventIdle struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	UnitID string `protobuf:"bytes,1,opt,name=unitID,proto3" json:"unitID,omitempty"`
+	Seq    uint32 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
 }
 
 func (x *EventIdle) Reset() {
@@ -555,23 +738,28 @@ func (x *EventIdle) GetUnitID() string {
 	return ""
 }
 
-type Unit struct {
+func (x *EventIdle) GetSeq() uint32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// EventSnapshot carries the units changed since the baseline snapshot the
+// client has acknowledged. A zero baseTick means a full snapshot.
+type EventSnapshot struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ID         string    `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	X          float64   `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y          float64   `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
-	SpriteName string    `protobuf:"bytes,4,opt,name=spriteName,proto3" json:"spriteName,omitempty"`
-	Action     Action    `protobuf:"varint,5,opt,name=action,proto3,enum=events.Action" json:"action,omitempty"`
-	Frame      int32     `protobuf:"varint,6,opt,name=frame,proto3" json:"frame,omitempty"`
-	Direction  Direction `protobuf:"varint,7,opt,name=direction,proto3,enum=events.Direction" json:"direction,omitempty"`
-	Speed      float64   `protobuf:"fixed64,8,opt,name=speed,proto3" json:"speed,omitempty"`
+	Tick     uint64       `protobuf:"varint,1,opt,name=tick,proto3" json:"tick,omitempty"`
+	BaseTick uint64       `protobuf:"varint,2,opt,name=baseTick,proto3" json:"baseTick,omitempty"`
+	Units    []*UnitDelta `protobuf:"bytes,3,rep,name=units,proto3" json:"units,omitempty"`
+	Removed  []string     `protobuf:"bytes,4,rep,name=removed,proto3" json:"removed,omitempty"`
 }
 
-func (x *Unit) Reset() {
-	*x = Unit{}
+func (x *EventSnapshot) Reset() {
+	*x = EventSnapshot{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_events_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -579,13 +767,13 @@ func (x *Unit) Reset() {
 	}
 }
 
-func (x *Unit) String() string {
+func (x *EventSnapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Unit) ProtoMessage() {}
+func (*EventSnapshot) ProtoMessage() {}
 
-func (x *Unit) ProtoReflect() protoreflect.Message {
+func (x *EventSnapshot) ProtoReflect() protoreflect.Message {
 	mi := &file_events_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -597,197 +785,1056 @@ func (x *Unit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Unit.ProtoReflect.Descriptor instead.
-func (*Unit) Descriptor() ([]byte, []int) {
+// Deprecated: Use EventSnapshot.ProtoReflect.Descriptor instead.
+func (*EventSnapshot) Descriptor() ([]byte, []int) {
 	return file_events_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Unit) GetID() string {
+func (x *EventSnapshot) GetTick() uint64 {
 	if x != nil {
-		return x.ID
+		return x.Tick
 	}
-	return ""
+	return 0
 }
 
-func (x *Unit) GetX() float64 {
+func (x *EventSnapshot) GetBaseTick() uint64 {
 	if x != nil {
-		return x.X
+		return x.BaseTick
 	}
 	return 0
 }
 
-func (x *Unit) GetY() float64 {
+func (x *EventSnapshot) GetUnits() []*UnitDelta {
 	if x != nil {
-		return x.Y
+		return x.Units
 	}
-	return 0
+	return nil
 }
 
-func (x *Unit) GetSpriteName() string {
+func (x *EventSnapshot) GetRemoved() []string {
 	if x != nil {
-		return x.SpriteName
+		return x.Removed
 	}
-	return ""
+	return nil
 }
 
-func (x *Unit) GetAction() Action {
-	if x != nil {
-		return x.Action
+type EventAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tick uint64 `protobuf:"varint,1,opt,name=tick,proto3" json:"tick,omitempty"`
+}
+
+func (x *EventAck) Reset() {
+	*x = EventAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return Action_RUN
 }
 
-func (x *Unit) GetFrame() int32 {
+func (x *EventAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventAck) ProtoMessage() {}
+
+func (x *EventAck) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventAck.ProtoReflect.Descriptor instead.
+func (*EventAck) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EventAck) GetTick() uint64 {
 	if x != nil {
-		return x.Frame
+		return x.Tick
 	}
 	return 0
 }
 
-func (x *Unit) GetDirection() Direction {
-	if x != nil {
-		return x.Direction
+// EventEnterView tells the client a unit came into its area of interest.
+type EventEnterView struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Unit *Unit `protobuf:"bytes,1,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (x *EventEnterView) Reset() {
+	*x = EventEnterView{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return Direction_LEFT
 }
 
-func (x *Unit) GetSpeed() float64 {
+func (x *EventEnterView) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventEnterView) ProtoMessage() {}
+
+func (x *EventEnterView) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventEnterView.ProtoReflect.Descriptor instead.
+func (*EventEnterView) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EventEnterView) GetUnit() *Unit {
 	if x != nil {
-		return x.Speed
+		return x.Unit
 	}
-	return 0
+	return nil
 }
 
-var File_events_proto protoreflect.FileDescriptor
+// EventLeaveView tells the client a unit left its area of interest.
+type EventLeaveView struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_events_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
-	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xe2, 0x02, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12,
-	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x6e,
-	0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x65, 0x76, 0x65, 0x6e,
-	0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x48,
-	0x00, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x69,
-	0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
-	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73,
-	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x63, 0x6f,
-	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x48, 0x00, 0x52, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x12, 0x27,
-	0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x48,
-	0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x04, 0x69, 0x64, 0x6c, 0x65,
-	0x22, 0x41, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4f, 0x4e, 0x4e,
-	0x45, 0x43, 0x54, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e, 0x4e,
-	0x45, 0x43, 0x54, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x49, 0x54, 0x10, 0x02, 0x12,
-	0x08, 0x0a, 0x04, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x44, 0x4c,
-	0x45, 0x10, 0x04, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x30, 0x0a, 0x0c, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x20, 0x0a, 0x04, 0x75,
-	0x6e, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e,
-	0x74, 0x73, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0x29, 0x0a,
-	0x0f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
-	0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x22, 0xa3, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
-	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
-	0x49, 0x44, 0x12, 0x32, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x1c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x49, 0x6e, 0x69, 0x74, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x1a, 0x46, 0x0a, 0x0a, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x22, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55,
-	0x6e, 0x69, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x54,
-	0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x75,
-	0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x6e, 0x69,
-	0x74, 0x49, 0x44, 0x12, 0x2f, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
-	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x23, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x6c,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x22, 0xd7, 0x01, 0x0a, 0x04, 0x55, 0x6e,
-	0x69, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x49, 0x44, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78,
-	0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x12, 0x1e,
-	0x0a, 0x0a, 0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0a, 0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26,
-	0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e,
-	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x09,
-	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a,
-	0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x70,
-	0x65, 0x65, 0x64, 0x2a, 0x32, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x08, 0x0a, 0x04, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x49,
-	0x47, 0x48, 0x54, 0x10, 0x01, 0x12, 0x06, 0x0a, 0x02, 0x55, 0x50, 0x10, 0x02, 0x12, 0x08, 0x0a,
-	0x04, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x03, 0x2a, 0x1b, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x55, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x44,
-	0x4c, 0x45, 0x10, 0x01, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x70, 0x61, 0x74, 0x72, 0x69, 0x63, 0x6b, 0x2d, 0x6d, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	UnitID string `protobuf:"bytes,1,opt,name=unitID,proto3" json:"unitID,omitempty"`
 }
 
-var (
-	file_events_proto_rawDescOnce sync.Once
-	file_events_proto_rawDescData = file_events_proto_rawDesc
-)
+func (x *EventLeaveView) Reset() {
+	*x = EventLeaveView{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_events_proto_rawDescGZIP() []byte {
-	file_events_proto_rawDescOnce.Do(func() {
-		file_events_proto_rawDescData = protoimpl.X.CompressGZIP(file_events_proto_rawDescData)
-	})
-	return file_events_proto_rawDescData
+func (x *EventLeaveView) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_events_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
-var file_events_proto_goTypes = []interface{}{
-	(Direction)(0),          // 0: events.Direction
-	(Action)(0),             // 1: events.Action
-	(Event_Type)(0),         // 2: events.Event.Type
-	(*Event)(nil),           // 3: events.Event
-	(*EventConnect)(nil),    // 4: events.EventConnect
-	(*EventDisconnect)(nil), // 5: events.EventDisconnect
-	(*EventInit)(nil),       // 6: events.EventInit
-	(*EventMove)(nil),       // 7: events.EventMove
-	(*EventIdle)(nil),       // 8: events.EventIdle
-	(*Unit)(nil),            // 9: events.Unit
-	nil,                     // 10: events.EventInit.UnitsEntry
+func (*EventLeaveView) ProtoMessage() {}
+
+func (x *EventLeaveView) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_events_proto_depIdxs = []int32{
-	2,  // 0: events.Event.type:type_name -> events.Event.Type
-	4,  // 1: events.Event.connect:type_name -> events.EventConnect
-	5,  // 2: events.Event.disconnect:type_name -> events.EventDisconnect
-	6,  // 3: events.Event.init:type_name -> events.EventInit
-	7,  // 4: events.Event.move:type_name -> events.EventMove
-	8,  // 5: events.Event.idle:type_name -> events.EventIdle
-	9,  // 6: events.EventConnect.unit:type_name -> events.Unit
-	10, // 7: events.EventInit.units:type_name -> events.EventInit.UnitsEntry
-	0,  // 8: events.EventMove.direction:type_name -> events.Direction
-	1,  // 9: events.Unit.action:type_name -> events.Action
-	0,  // 10: events.Unit.direction:type_name -> events.Direction
-	9,  // 11: events.EventInit.UnitsEntry.value:type_name -> events.Unit
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+
+// Deprecated: Use EventLeaveView.ProtoReflect.Descriptor instead.
+func (*EventLeaveView) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{9}
 }
 
-func init() { file_events_proto_init() }
-func file_events_proto_init() {
-	if File_events_proto != nil {
-		return
+func (x *EventLeaveView) GetUnitID() string {
+	if x != nil {
+		return x.UnitID
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_events_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event); i {
+	return ""
+}
+
+// EventShutdown is the last event sent before the server closes the connection.
+type EventShutdown struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *EventShutdown) Reset() {
+	*x = EventShutdown{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventShutdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventShutdown) ProtoMessage() {}
+
+func (x *EventShutdown) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventShutdown.ProtoReflect.Descriptor instead.
+func (*EventShutdown) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EventShutdown) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// EventAttack is a melee attack of the unit in the direction it faces, which
+// the server fills in.
+type EventAttack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UnitID    string    `protobuf:"bytes,1,opt,name=unitID,proto3" json:"unitID,omitempty"`
+	Direction Direction `protobuf:"varint,2,opt,name=direction,proto3,enum=events.Direction" json:"direction,omitempty"`
+}
+
+func (x *EventAttack) Reset() {
+	*x = EventAttack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventAttack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventAttack) ProtoMessage() {}
+
+func (x *EventAttack) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventAttack.ProtoReflect.Descriptor instead.
+func (*EventAttack) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EventAttack) GetUnitID() string {
+	if x != nil {
+		return x.UnitID
+	}
+	return ""
+}
+
+func (x *EventAttack) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_LEFT
+}
+
+// EventDamage tells the unit was hit, hp is what it has left.
+type EventDamage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UnitID     string `protobuf:"bytes,1,opt,name=unitID,proto3" json:"unitID,omitempty"`
+	AttackerID string `protobuf:"bytes,2,opt,name=attackerID,proto3" json:"attackerID,omitempty"`
+	Amount     int32  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Hp         int32  `protobuf:"varint,4,opt,name=hp,proto3" json:"hp,omitempty"`
+}
+
+func (x *EventDamage) Reset() {
+	*x = EventDamage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventDamage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventDamage) ProtoMessage() {}
+
+func (x *EventDamage) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventDamage.ProtoReflect.Descriptor instead.
+func (*EventDamage) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EventDamage) GetUnitID() string {
+	if x != nil {
+		return x.UnitID
+	}
+	return ""
+}
+
+func (x *EventDamage) GetAttackerID() string {
+	if x != nil {
+		return x.AttackerID
+	}
+	return ""
+}
+
+func (x *EventDamage) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *EventDamage) GetHp() int32 {
+	if x != nil {
+		return x.Hp
+	}
+	return 0
+}
+
+type EventDeath struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UnitID   string `protobuf:"bytes,1,opt,name=unitID,proto3" json:"unitID,omitempty"`
+	KillerID string `protobuf:"bytes,2,opt,name=killerID,proto3" json:"killerID,omitempty"`
+	// Seconds until the unit respawns.
+	RespawnIn float64 `protobuf:"fixed64,3,opt,name=respawnIn,proto3" json:"respawnIn,omitempty"`
+}
+
+func (x *EventDeath) Reset() {
+	*x = EventDeath{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventDeath) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventDeath) ProtoMessage() {}
+
+func (x *EventDeath) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventDeath.ProtoReflect.Descriptor instead.
+func (*EventDeath) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EventDeath) GetUnitID() string {
+	if x != nil {
+		return x.UnitID
+	}
+	return ""
+}
+
+func (x *EventDeath) GetKillerID() string {
+	if x != nil {
+		return x.KillerID
+	}
+	return ""
+}
+
+func (x *EventDeath) GetRespawnIn() float64 {
+	if x != nil {
+		return x.RespawnIn
+	}
+	return 0
+}
+
+// EventRespawn brings a dead unit back at a spawn point.
+type EventRespawn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Unit *Unit `protobuf:"bytes,1,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (x *EventRespawn) Reset() {
+	*x = EventRespawn{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventRespawn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventRespawn) ProtoMessage() {}
+
+func (x *EventRespawn) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventRespawn.ProtoReflect.Descriptor instead.
+func (*EventRespawn) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EventRespawn) GetUnit() *Unit {
+	if x != nil {
+		return x.Unit
+	}
+	return nil
+}
+
+type Unit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID         string    `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	X          float64   `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y          float64   `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	SpriteName string    `protobuf:"bytes,4,opt,name=spriteName,proto3" json:"spriteName,omitempty"`
+	Action     Action    `protobuf:"varint,5,opt,name=action,proto3,enum=events.Action" json:"action,omitempty"`
+	Frame      int32     `protobuf:"varint,6,opt,name=frame,proto3" json:"frame,omitempty"`
+	Direction  Direction `protobuf:"varint,7,opt,name=direction,proto3,enum=events.Direction" json:"direction,omitempty"`
+	// Pixels per second.
+	Speed        float64 `protobuf:"fixed64,8,opt,name=speed,proto3" json:"speed,omitempty"`
+	LastInputSeq uint32  `protobuf:"varint,9,opt,name=lastInputSeq,proto3" json:"lastInputSeq,omitempty"`
+	Vx           float64 `protobuf:"fixed64,10,opt,name=vx,proto3" json:"vx,omitempty"`
+	Vy           float64 `protobuf:"fixed64,11,opt,name=vy,proto3" json:"vy,omitempty"`
+	Hp           int32   `protobuf:"varint,12,opt,name=hp,proto3" json:"hp,omitempty"`
+	MaxHp        int32   `protobuf:"varint,13,opt,name=maxHp,proto3" json:"maxHp,omitempty"`
+	// Can't be hurt for a while after spawning.
+	Invulnerable bool `protobuf:"varint,14,opt,name=invulnerable,proto3" json:"invulnerable,omitempty"`
+}
+
+func (x *Unit) Reset() {
+	*x = Unit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Unit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Unit) ProtoMessage() {}
+
+func (x *Unit) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Unit.ProtoReflect.Descriptor instead.
+func (*Unit) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Unit) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Unit) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Unit) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Unit) GetSpriteName() string {
+	if x != nil {
+		return x.SpriteName
+	}
+	return ""
+}
+
+func (x *Unit) GetAction() Action {
+	if x != nil {
+		return x.Action
+	}
+	return Action_RUN
+}
+
+func (x *Unit) GetFrame() int32 {
+	if x != nil {
+		return x.Frame
+	}
+	return 0
+}
+
+func (x *Unit) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_LEFT
+}
+
+func (x *Unit) GetSpeed() float64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *Unit) GetLastInputSeq() uint32 {
+	if x != nil {
+		return x.LastInputSeq
+	}
+	return 0
+}
+
+func (x *Unit) GetVx() float64 {
+	if x != nil {
+		return x.Vx
+	}
+	return 0
+}
+
+func (x *Unit) GetVy() float64 {
+	if x != nil {
+		return x.Vy
+	}
+	return 0
+}
+
+func (x *Unit) GetHp() int32 {
+	if x != nil {
+		return x.Hp
+	}
+	return 0
+}
+
+func (x *Unit) GetMaxHp() int32 {
+	if x != nil {
+		return x.MaxHp
+	}
+	return 0
+}
+
+func (x *Unit) GetInvulnerable() bool {
+	if x != nil {
+		return x.Invulnerable
+	}
+	return false
+}
+
+// UnitDelta holds only the Unit fields that changed relative to the baseline.
+type UnitDelta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID           string     `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	X            *float64   `protobuf:"fixed64,2,opt,name=x,proto3,oneof" json:"x,omitempty"`
+	Y            *float64   `protobuf:"fixed64,3,opt,name=y,proto3,oneof" json:"y,omitempty"`
+	SpriteName   *string    `protobuf:"bytes,4,opt,name=spriteName,proto3,oneof" json:"spriteName,omitempty"`
+	Action       *Action    `protobuf:"varint,5,opt,name=action,proto3,enum=events.Action,oneof" json:"action,omitempty"`
+	Frame        *int32     `protobuf:"varint,6,opt,name=frame,proto3,oneof" json:"frame,omitempty"`
+	Direction    *Direction `protobuf:"varint,7,opt,name=direction,proto3,enum=events.Direction,oneof" json:"direction,omitempty"`
+	Speed        *float64   `protobuf:"fixed64,8,opt,name=speed,proto3,oneof" json:"speed,omitempty"`
+	LastInputSeq *uint32    `protobuf:"varint,9,opt,name=lastInputSeq,proto3,oneof" json:"lastInputSeq,omitempty"`
+	Vx           *float64   `protobuf:"fixed64,10,opt,name=vx,proto3,oneof" json:"vx,omitempty"`
+	Vy           *float64   `protobuf:"fixed64,11,opt,name=vy,proto3,oneof" json:"vy,omitempty"`
+	Hp           *int32     `protobuf:"varint,12,opt,name=hp,proto3,oneof" json:"hp,omitempty"`
+	MaxHp        *int32     `protobuf:"varint,13,opt,name=maxHp,proto3,oneof" json:"maxHp,omitempty"`
+	Invulnerable *bool      `protobuf:"varint,14,opt,name=invulnerable,proto3,oneof" json:"invulnerable,omitempty"`
+}
+
+func (x *UnitDelta) Reset() {
+	*x = UnitDelta{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_events_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnitDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnitDelta) ProtoMessage() {}
+
+func (x *UnitDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnitDelta.ProtoReflect.Descriptor instead.
+func (*UnitDelta) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UnitDelta) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *UnitDelta) GetX() float64 {
+	if x != nil && x.X != nil {
+		return *x.X
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetY() float64 {
+	if x != nil && x.Y != nil {
+		return *x.Y
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetSpriteName() string {
+	if x != nil && x.SpriteName != nil {
+		return *x.SpriteName
+	}
+	return ""
+}
+
+func (x *UnitDelta) GetAction() Action {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return Action_RUN
+}
+
+func (x *UnitDelta) GetFrame() int32 {
+	if x != nil && x.Frame != nil {
+		return *x.Frame
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetDirection() Direction {
+	if x != nil && x.Direction != nil {
+		return *x.Direction
+	}
+	return Direction_LEFT
+}
+
+func (x *UnitDelta) GetSpeed() float64 {
+	if x != nil && x.Speed != nil {
+		return *x.Speed
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetLastInputSeq() uint32 {
+	if x != nil && x.LastInputSeq != nil {
+		return *x.LastInputSeq
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetVx() float64 {
+	if x != nil && x.Vx != nil {
+		return *x.Vx
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetVy() float64 {
+	if x != nil && x.Vy != nil {
+		return *x.Vy
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetHp() int32 {
+	if x != nil && x.Hp != nil {
+		return *x.Hp
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetMaxHp() int32 {
+	if x != nil && x.MaxHp != nil {
+		return *x.MaxHp
+	}
+	return 0
+}
+
+func (x *UnitDelta) GetInvulnerable() bool {
+	if x != nil && x.Invulnerable != nil {
+		return *x.Invulnerable
+	}
+	return false
+}
+
+var File_events_proto protoreflect.FileDescriptor
+
+var file_events_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xa0, 0x07, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x48,
+	0x00, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x69,
+	0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x48, 0x00, 0x52, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x12, 0x27,
+	0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x48,
+	0x00, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x04, 0x69, 0x64, 0x6c, 0x65,
+	0x12, 0x33, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x08, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x24, 0x0a, 0x03, 0x61, 0x63, 0x6b, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x12, 0x36, 0x0a, 0x09, 0x65,
+	0x6e, 0x74, 0x65, 0x72, 0x56, 0x69, 0x65, 0x77, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x74,
+	0x65, 0x72, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00, 0x52, 0x09, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x56,
+	0x69, 0x65, 0x77, 0x12, 0x36, 0x0a, 0x09, 0x6c, 0x65, 0x61, 0x76, 0x65, 0x56, 0x69, 0x65, 0x77,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00,
+	0x52, 0x09, 0x6c, 0x65, 0x61, 0x76, 0x65, 0x56, 0x69, 0x65, 0x77, 0x12, 0x33, 0x0a, 0x08, 0x73,
+	0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x68, 0x75, 0x74,
+	0x64, 0x6f, 0x77, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e,
+	0x12, 0x2d, 0x0a, 0x06, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x06, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x12,
+	0x2d, 0x0a, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61,
+	0x6d, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x2a,
+	0x0a, 0x05, 0x64, 0x65, 0x61, 0x74, 0x68, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x61, 0x74,
+	0x68, 0x48, 0x00, 0x52, 0x05, 0x64, 0x65, 0x61, 0x74, 0x68, 0x12, 0x30, 0x0a, 0x07, 0x72, 0x65,
+	0x73, 0x70, 0x61, 0x77, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x61, 0x77,
+	0x6e, 0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x22, 0xbc, 0x01, 0x0a,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54,
+	0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54,
+	0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x49, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04,
+	0x4d, 0x4f, 0x56, 0x45, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x44, 0x4c, 0x45, 0x10, 0x04,
+	0x12, 0x0c, 0x0a, 0x08, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54, 0x10, 0x06, 0x12, 0x07,
+	0x0a, 0x03, 0x41, 0x43, 0x4b, 0x10, 0x07, 0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x4e, 0x54, 0x45, 0x52,
+	0x5f, 0x56, 0x49, 0x45, 0x57, 0x10, 0x08, 0x12, 0x0e, 0x0a, 0x0a, 0x4c, 0x45, 0x41, 0x56, 0x45,
+	0x5f, 0x56, 0x49, 0x45, 0x57, 0x10, 0x09, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x48, 0x55, 0x54, 0x44,
+	0x4f, 0x57, 0x4e, 0x10, 0x0a, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x54, 0x54, 0x41, 0x43, 0x4b, 0x10,
+	0x0b, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x41, 0x4d, 0x41, 0x47, 0x45, 0x10, 0x0c, 0x12, 0x09, 0x0a,
+	0x05, 0x44, 0x45, 0x41, 0x54, 0x48, 0x10, 0x0d, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x53, 0x50,
+	0x41, 0x57, 0x4e, 0x10, 0x0e, 0x22, 0x04, 0x08, 0x05, 0x10, 0x05, 0x42, 0x06, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x08, 0x22, 0x30, 0x0a, 0x0c, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x20, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x2e, 0x55, 0x6e, 0x69, 0x74, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0x29, 0x0a, 0x0f, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x22, 0xa9, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x49, 0x6e, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x44,
+	0x12, 0x32, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x6e,
+	0x69, 0x74, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x75,
+	0x6e, 0x69, 0x74, 0x73, 0x1a, 0x46, 0x0a, 0x0a, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x22, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x6e, 0x69,
+	0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x4a, 0x04, 0x08, 0x03,
+	0x10, 0x04, 0x22, 0x86, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x76, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x12, 0x2f, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x76,
+	0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x02, 0x76, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x76,
+	0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x02, 0x76, 0x79, 0x22, 0x35, 0x0a, 0x09, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x74,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44,
+	0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x73,
+	0x65, 0x71, 0x22, 0x82, 0x01, 0x0a, 0x0d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x61, 0x73, 0x65,
+	0x54, 0x69, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x62, 0x61, 0x73, 0x65,
+	0x54, 0x69, 0x63, 0x6b, 0x12, 0x27, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x6e, 0x69,
+	0x74, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0x1e, 0x0a, 0x08, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x41, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x22, 0x32, 0x0a, 0x0e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x45, 0x6e, 0x74, 0x65, 0x72, 0x56, 0x69, 0x65, 0x77, 0x12, 0x20, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x2e, 0x55, 0x6e, 0x69, 0x74, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0x28, 0x0a, 0x0e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x56, 0x69, 0x65, 0x77, 0x12, 0x16, 0x0a,
+	0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
+	0x6e, 0x69, 0x74, 0x49, 0x44, 0x22, 0x27, 0x0a, 0x0d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x68,
+	0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x56,
+	0x0a, 0x0b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x16, 0x0a,
+	0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
+	0x6e, 0x69, 0x74, 0x49, 0x44, 0x12, 0x2f, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6d, 0x0a, 0x0b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44,
+	0x61, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x12, 0x1e, 0x0a,
+	0x0a, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x68, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x68, 0x70, 0x22, 0x5e, 0x0a, 0x0a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x65,
+	0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x6b,
+	0x69, 0x6c, 0x6c, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b,
+	0x69, 0x6c, 0x6c, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x61,
+	0x77, 0x6e, 0x49, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70,
+	0x61, 0x77, 0x6e, 0x49, 0x6e, 0x22, 0x30, 0x0a, 0x0c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x61, 0x77, 0x6e, 0x12, 0x20, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x6e, 0x69,
+	0x74, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0xe5, 0x02, 0x0a, 0x04, 0x55, 0x6e, 0x69, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44,
+	0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78, 0x12, 0x0c,
+	0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x12, 0x1e, 0x0a, 0x0a,
+	0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x09, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x70, 0x65, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65,
+	0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x65,
+	0x71, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x49, 0x6e, 0x70,
+	0x75, 0x74, 0x53, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x76, 0x78, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x02, 0x76, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x76, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x02, 0x76, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x68, 0x70, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x68, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61, 0x78, 0x48, 0x70, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6d, 0x61, 0x78, 0x48, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x69,
+	0x6e, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x69, 0x6e, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x22,
+	0xb4, 0x04, 0x0a, 0x09, 0x55, 0x6e, 0x69, 0x74, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x0e, 0x0a,
+	0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x11, 0x0a,
+	0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x01, 0x78, 0x88, 0x01, 0x01,
+	0x12, 0x11, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x01, 0x79,
+	0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x73, 0x70, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0a, 0x73, 0x70, 0x72, 0x69, 0x74,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x03, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x04, 0x52, 0x05, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x34, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x05, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x01, 0x48, 0x06, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x27, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x65,
+	0x71, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x07, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x53, 0x65, 0x71, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a, 0x02, 0x76, 0x78,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x48, 0x08, 0x52, 0x02, 0x76, 0x78, 0x88, 0x01, 0x01, 0x12,
+	0x13, 0x0a, 0x02, 0x76, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x48, 0x09, 0x52, 0x02, 0x76,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a, 0x02, 0x68, 0x70, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x0a, 0x52, 0x02, 0x68, 0x70, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6d, 0x61, 0x78,
+	0x48, 0x70, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0b, 0x52, 0x05, 0x6d, 0x61, 0x78, 0x48,
+	0x70, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x69, 0x6e, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72,
+	0x61, 0x62, 0x6c, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0c, 0x52, 0x0c, 0x69, 0x6e,
+	0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x04, 0x0a,
+	0x02, 0x5f, 0x78, 0x42, 0x04, 0x0a, 0x02, 0x5f, 0x79, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x70,
+	0x72, 0x69, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x42, 0x0c, 0x0a,
+	0x0a, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f,
+	0x73, 0x70, 0x65, 0x65, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x53, 0x65, 0x71, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x76, 0x78, 0x42, 0x05, 0x0a,
+	0x03, 0x5f, 0x76, 0x79, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x68, 0x70, 0x42, 0x08, 0x0a, 0x06, 0x5f,
+	0x6d, 0x61, 0x78, 0x48, 0x70, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x69, 0x6e, 0x76, 0x75, 0x6c, 0x6e,
+	0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x2a, 0x32, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x00, 0x12, 0x09, 0x0a,
+	0x05, 0x52, 0x49, 0x47, 0x48, 0x54, 0x10, 0x01, 0x12, 0x06, 0x0a, 0x02, 0x55, 0x50, 0x10, 0x02,
+	0x12, 0x08, 0x0a, 0x04, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x03, 0x2a, 0x25, 0x0a, 0x06, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x55, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a,
+	0x04, 0x49, 0x44, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x45, 0x41, 0x44, 0x10,
+	0x02, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x70, 0x61, 0x74, 0x72, 0x69, 0x63, 0x6b, 0x2d, 0x6d, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_events_proto_rawDescOnce sync.Once
+	file_events_proto_rawDescData = file_events_proto_rawDesc
+)
+
+func file_events_proto_rawDescGZIP() []byte {
+	file_events_proto_rawDescOnce.Do(func() {
+		file_events_proto_rawDescData = protoimpl.X.CompressGZIP(file_events_proto_rawDescData)
+	})
+	return file_events_proto_rawDescData
+}
+
+var file_events_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_events_proto_goTypes = []interface{}{
+	(Direction)(0),          // 0: events.Direction
+	(Action)(0),             // 1: events.Action
+	(Event_Type)(0),         // 2: events.Event.Type
+	(*Event)(nil),           // 3: events.Event
+	(*EventConnect)(nil),    // 4: events.EventConnect
+	(*EventDisconnect)(nil), // 5: events.EventDisconnect
+	(*EventInit)(nil),       // 6: events.EventInit
+	(*EventMove)(nil),       // 7: events.EventMove
+	(*EventIdle)(nil),       // 8: events.EventIdle
+	(*EventSnapshot)(nil),   // 9: events.EventSnapshot
+	(*EventAck)(nil),        // 10: events.EventAck
+	(*EventEnterView)(nil),  // 11: events.EventEnterView
+	(*EventLeaveView)(nil),  // 12: events.EventLeaveView
+	(*EventShutdown)(nil),   // 13: events.EventShutdown
+	(*EventAttack)(nil),     // 14: events.EventAttack
+	(*EventDamage)(nil),     // 15: events.EventDamage
+	(*EventDeath)(nil),      // 16: events.EventDeath
+	(*EventRespawn)(nil),    // 17: events.EventRespawn
+	(*Unit)(nil),            // 18: events.Unit
+	(*UnitDelta)(nil),       // 19: events.UnitDelta
+	nil,                     // 20: events.EventInit.UnitsEntry
+}
+var file_events_proto_depIdxs = []int32{
+	2,  // 0: events.Event.type:type_name -> events.Event.Type
+	4,  // 1: events.Event.connect:type_name -> events.EventConnect
+	5,  // 2: events.Event.disconnect:type_name -> events.EventDisconnect
+	6,  // 3: events.Event.init:type_name -> events.EventInit
+	7,  // 4: events.Event.move:type_name -> events.EventMove
+	8,  // 5: events.Event.idle:type_name -> events.EventIdle
+	9,  // 6: events.Event.snapshot:type_name -> events.EventSnapshot
+	10, // 7: events.Event.ack:type_name -> events.EventAck
+	11, // 8: events.Event.enterView:type_name -> events.EventEnterView
+	12, // 9: events.Event.leaveView:type_name -> events.EventLeaveView
+	13, // 10: events.Event.shutdown:type_name -> events.EventShutdown
+	14, // 11: events.Event.attack:type_name -> events.EventAttack
+	15, // 12: events.Event.damage:type_name -> events.EventDamage
+	16, // 13: events.Event.death:type_name -> events.EventDeath
+	17, // 14: events.Event.respawn:type_name -> events.EventRespawn
+	18, // 15: events.EventConnect.unit:type_name -> events.Unit
+	20, // 16: events.EventInit.units:type_name -> events.EventInit.UnitsEntry
+	0,  // 17: events.EventMove.direction:type_name -> events.Direction
+	19, // 18: events.EventSnapshot.units:type_name -> events.UnitDelta
+	18, // 19: events.EventEnterView.unit:type_name -> events.Unit
+	0,  // 20: events.EventAttack.direction:type_name -> events.Direction
+	18, // 21: events.EventRespawn.unit:type_name -> events.Unit
+	1,  // 22: events.Unit.action:type_name -> events.Action
+	0,  // 23: events.Unit.direction:type_name -> events.Direction
+	1,  // 24: events.UnitDelta.action:type_name -> events.Action
+	0,  // 25: events.UnitDelta.direction:type_name -> events.Direction
+	18, // 26: events.EventInit.UnitsEntry.value:type_name -> events.Unit
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
+}
+
+func init() { file_events_proto_init() }
+func file_events_proto_init() {
+	if File_events_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_events_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -859,6 +1906,114 @@ func file_events_proto_init() {
 			}
 		}
 		file_events_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventEnterView); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventLeaveView); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventShutdown); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventAttack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventDamage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventDeath); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventRespawn); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_events_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Unit); i {
 			case 0:
 				return &v.state
@@ -870,6 +2025,18 @@ func file_events_proto_init() {
 				return nil
 			}
 		}
+		file_events_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnitDelta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_events_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*Event_Connect)(nil),
@@ -877,14 +2044,24 @@ func file_events_proto_init() {
 		(*Event_Init)(nil),
 		(*Event_Move)(nil),
 		(*Event_Idle)(nil),
+		(*Event_Snapshot)(nil),
+		(*Event_Ack)(nil),
+		(*Event_EnterView)(nil),
+		(*Event_LeaveView)(nil),
+		(*Event_Shutdown)(nil),
+		(*Event_Attack)(nil),
+		(*Event_Damage)(nil),
+		(*Event_Death)(nil),
+		(*Event_Respawn)(nil),
 	}
+	file_events_proto_msgTypes[16].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_events_proto_rawDesc,
 			NumEnums:      3,
-			NumMessages:   8,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
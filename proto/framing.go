@@ -0,0 +1,66 @@
+package events
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// A websocket message carries one or more events, each one prefixed with its
+// varint encoded length, so that queued events can be batched into a single
+// frame and split apart again on the other side.
+
+var errMalformedFrame = errors.New("malformed event frame")
+
+// AppendFrame appends the marshalled event msg to buf as a length-delimited
+// record.
+func AppendFrame(buf []byte, msg []byte) []byte {
+	buf = protowire.AppendVarint(buf, uint64(len(msg)))
+	return append(buf, msg...)
+}
+
+// MarshalFrame marshals the events into a single length-delimited frame.
+func MarshalFrame(events ...*Event) ([]byte, error) {
+	var buf []byte
+	for _, e := range events {
+		msg, err := proto.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		buf = AppendFrame(buf, msg)
+	}
+	return buf, nil
+}
+
+// SplitFrame splits a frame into the marshalled events it carries.
+func SplitFrame(data []byte) ([][]byte, error) {
+	var msgs [][]byte
+	for len(data) > 0 {
+		size, n := protowire.ConsumeVarint(data)
+		if n < 0 || size > uint64(len(data)-n) {
+			return nil, errMalformedFrame
+		}
+		data = data[n:]
+		msgs = append(msgs, data[:size])
+		data = data[size:]
+	}
+	return msgs, nil
+}
+
+// UnmarshalFrame decodes every event carried by a frame.
+func UnmarshalFrame(data []byte) ([]*Event, error) {
+	msgs, err := SplitFrame(data)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*Event, 0, len(msgs))
+	for _, msg := range msgs {
+		var e Event
+		if err := proto.Unmarshal(msg, &e); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, nil
+}
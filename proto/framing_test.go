@@ -0,0 +1,113 @@
+package events
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	batch := []*Event{
+		{
+			Type: Event_MOVE,
+			Data: &Event_Move{Move: &EventMove{UnitID: "a", Direction: Direction_LEFT, Seq: 1, Vx: -1}},
+		},
+		{
+			Type: Event_IDLE,
+			Data: &Event_Idle{Idle: &EventIdle{UnitID: "b", Seq: 2}},
+		},
+		{
+			Type: Event_ACK,
+			Data: &Event_Ack{Ack: &EventAck{Tick: 300}},
+		},
+	}
+
+	frame, err := MarshalFrame(batch...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalFrame(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(batch) {
+		t.Fatalf("got %d events, want %d", len(got), len(batch))
+	}
+	for i := range batch {
+		if !proto.Equal(got[i], batch[i]) {
+			t.Errorf("event %d = %v, want %v", i, got[i], batch[i])
+		}
+	}
+}
+
+func TestAppendFrameBatchesQueuedMessages(t *testing.T) {
+	msgs := [][]byte{[]byte("first"), {}, bytes.Repeat([]byte{7}, 300)}
+
+	var frame []byte
+	for _, msg := range msgs {
+		frame = AppendFrame(frame, msg)
+	}
+	got, err := SplitFrame(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(msgs) {
+		t.Fatalf("got %d messages, want %d", len(got), len(msgs))
+	}
+	for i := range msgs {
+		if !bytes.Equal(got[i], msgs[i]) {
+			t.Errorf("message %d = %x, want %x", i, got[i], msgs[i])
+		}
+	}
+}
+
+func TestEmptyFrames(t *testing.T) {
+	msgs, err := SplitFrame(nil)
+	if err != nil || len(msgs) != 0 {
+		t.Errorf("SplitFrame(nil) = %v, %v, want no message", msgs, err)
+	}
+
+	frame, err := MarshalFrame()
+	if err != nil || len(frame) != 0 {
+		t.Errorf("MarshalFrame() = %x, %v, want an empty frame", frame, err)
+	}
+
+	// An event with every field unset marshals to a zero-length record.
+	frame, err = MarshalFrame(&Event{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(frame, []byte{0}) {
+		t.Errorf("MarshalFrame(empty event) = %x, want 00", frame)
+	}
+	events, err := UnmarshalFrame(frame)
+	if err != nil || len(events) != 1 || !proto.Equal(events[0], &Event{}) {
+		t.Errorf("UnmarshalFrame(%x) = %v, %v, want one empty event", frame, events, err)
+	}
+}
+
+func TestMalformedFrames(t *testing.T) {
+	valid := AppendFrame(nil, []byte("ok"))
+
+	tests := []struct {
+		name  string
+		frame []byte
+	}{
+		{"truncated length prefix", []byte{0x80}},
+		{"truncated prefix after a record", append(append([]byte{}, valid...), 0xff, 0xff)},
+		{"prefix longer than the buffer", []byte{5, 1, 2}},
+		{"last record cut short", append(append([]byte{}, valid...), 3, 'a')},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := SplitFrame(test.frame); !errors.Is(err, errMalformedFrame) {
+				t.Errorf("SplitFrame(%x) error = %v, want %v", test.frame, err, errMalformedFrame)
+			}
+			if _, err := UnmarshalFrame(test.frame); !errors.Is(err, errMalformedFrame) {
+				t.Errorf("UnmarshalFrame(%x) error = %v, want %v", test.frame, err, errMalformedFrame)
+			}
+		})
+	}
+}
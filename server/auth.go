@@ -0,0 +1,116 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// How long the tokens issued by the login endpoint are valid.
+	tokenLifetime = 30 * 24 * time.Hour
+
+	// How long after expiring a token can still be swapped for a new one of
+	// the same player.
+	renewalGrace = 7 * 24 * time.Hour
+)
+
+var (
+	errInvalidToken = errors.New("invalid token")
+	errExpiredToken = errors.New("token expired")
+)
+
+// claims are the signed content of a token.
+type claims struct {
+	PlayerID string `json:"sub"`
+	Expires  int64  `json:"exp"`
+}
+
+// authenticator issues and verifies the tokens identifying the players. A
+// token is the base64 encoded claims and their HMAC-SHA256 signature, joined
+// by a dot.
+type authenticator struct {
+	secret []byte
+}
+
+// newAuthenticator signs the tokens with the secret. Without a secret a random
+// one is used, and the tokens don't survive a restart.
+func newAuthenticator(secret string) (*authenticator, error) {
+	if secret != "" {
+		return &authenticator{secret: []byte(secret)}, nil
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	return &authenticator{secret: random}, nil
+}
+
+// issue returns a token for the player.
+func (a *authenticator) issue(playerID string) (string, error) {
+	payload, err := json.Marshal(claims{
+		PlayerID: playerID,
+		Expires:  time.Now().Add(tokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + a.sign(encoded), nil
+}
+
+// verify returns the ID of the player the token was issued to.
+func (a *authenticator) verify(token string) (string, error) {
+	c, err := a.parse(token)
+	if err != nil {
+		return "", err
+	}
+	if time.Now().Unix() >= c.Expires {
+		return "", errExpiredToken
+	}
+	return c.PlayerID, nil
+}
+
+// renewable returns the ID of the player the token was issued to, unless the
+// token expired more than renewalGrace ago.
+func (a *authenticator) renewable(token string) (string, error) {
+	c, err := a.parse(token)
+	if err != nil {
+		return "", err
+	}
+	if time.Now().After(time.Unix(c.Expires, 0).Add(renewalGrace)) {
+		return "", errExpiredToken
+	}
+	return c.PlayerID, nil
+}
+
+// parse checks the signature of the token and returns its claims, whether the
+// token has expired or not.
+func (a *authenticator) parse(token string) (*claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(a.sign(encoded))) {
+		return nil, errInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	var c claims
+	if err = json.Unmarshal(payload, &c); err != nil || c.PlayerID == "" {
+		return nil, errInvalidToken
+	}
+	return &c, nil
+}
+
+func (a *authenticator) sign(encoded string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
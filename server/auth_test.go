@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tokenExpiring returns a token of the player signed by the authenticator,
+// expiring at the given time.
+func tokenExpiring(t *testing.T, a *authenticator, playerID string, expires time.Time) string {
+	t.Helper()
+	payload, err := json.Marshal(claims{PlayerID: playerID, Expires: expires.Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + a.sign(encoded)
+}
+
+func TestIssueVerify(t *testing.T) {
+	a, err := newAuthenticator("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := a.issue("player")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := a.verify(token)
+	if err != nil || id != "player" {
+		t.Errorf("verify = %q, %v, want player", id, err)
+	}
+
+	// A restarted server signing with the same secret accepts the token.
+	again, _ := newAuthenticator("secret")
+	if id, err := again.verify(token); err != nil || id != "player" {
+		t.Errorf("verify after restart = %q, %v, want player", id, err)
+	}
+}
+
+func TestVerifyRejectsForgedTokens(t *testing.T) {
+	a, _ := newAuthenticator("secret")
+	token, _ := a.issue("player")
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	other, _ := newAuthenticator("other secret")
+	foreign, _ := other.issue("player")
+
+	random, _ := newAuthenticator("")
+	unsigned, _ := random.issue("player")
+
+	forged, _ := json.Marshal(claims{PlayerID: "admin", Expires: time.Now().Add(time.Hour).Unix()})
+
+	tests := map[string]string{
+		"empty":              "",
+		"no signature":       encoded,
+		"tampered payload":   base64.RawURLEncoding.EncodeToString(forged) + "." + signature,
+		"tampered signature": encoded + "." + strings.ToUpper(signature),
+		"swapped parts":      signature + "." + encoded,
+		"other secret":       foreign,
+		"random secret":      unsigned,
+		"no player":          tokenExpiring(t, a, "", time.Now().Add(time.Hour)),
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if id, err := a.verify(token); !errors.Is(err, errInvalidToken) {
+				t.Errorf("verify = %q, %v, want %v", id, err, errInvalidToken)
+			}
+			if id, err := a.renewable(token); !errors.Is(err, errInvalidToken) {
+				t.Errorf("renewable = %q, %v, want %v", id, err, errInvalidToken)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsExpiredTokens(t *testing.T) {
+	a, _ := newAuthenticator("secret")
+	token := tokenExpiring(t, a, "player", time.Now().Add(-time.Minute))
+
+	if id, err := a.verify(token); !errors.Is(err, errExpiredToken) {
+		t.Errorf("verify = %q, %v, want %v", id, err, errExpiredToken)
+	}
+}
+
+func TestRenewable(t *testing.T) {
+	a, _ := newAuthenticator("secret")
+
+	tests := []struct {
+		name    string
+		expires time.Time
+		err     error
+	}{
+		{"valid", time.Now().Add(tokenLifetime), nil},
+		{"just expired", time.Now().Add(-time.Minute), nil},
+		{"expired within the grace", time.Now().Add(-renewalGrace + time.Hour), nil},
+		{"expired past the grace", time.Now().Add(-renewalGrace - time.Hour), errExpiredToken},
+		{"expired long ago", time.Now().Add(-365 * 24 * time.Hour), errExpiredToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := a.renewable(tokenExpiring(t, a, "player", tt.expires))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("renewable = %q, %v, want %v", id, err, tt.err)
+			}
+			if err == nil && id != "player" {
+				t.Errorf("renewable = %q, want player", id)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -50,6 +51,57 @@ type Client struct {
 
 	// Buffered channel of outbound messages.
 	send chan []byte
+
+	// ID of the unit controlled by this client.
+	unitID string
+
+	// Sessions of the players, keeping the unit during reconnections.
+	sessions *sessions
+
+	// Leaves the session once, when the first pump stops.
+	leaving sync.Once
+
+	// Snapshots sent to the client, used as delta baselines.
+	snapshots snapshots
+
+	// Units in the client's area of interest, the ones sent in INIT until the
+	// first snapshot. Owned by the hub goroutine once the client is
+	// registered.
+	visible map[string]bool
+
+	// Close message written once the send channel is closed. Set by the hub
+	// before closing the channel, an empty close message is written if nil.
+	closeMsg []byte
+}
+
+// leave detaches the client from its session. The unit stops and stays in
+// the world, invulnerable, until the grace period is over, in case the client
+// reconnects.
+func (c *Client) leave(world *w.World) {
+	c.leaving.Do(func() {
+		detached := c.sessions.detach(c, func() {
+			removeDisconnectedUnit(c.hub, world, c.unitID)
+		})
+		if !detached {
+			return
+		}
+		logger.Info("client left, keeping its unit", zap.String("unitId", c.unitID))
+	})
+}
+
+// sees reports whether events about the unit concern the client.
+func (c *Client) sees(unitID string) bool {
+	return unitID == c.unitID || c.visible[unitID]
+}
+
+// setVisible records the units the client was sent, other than its own.
+func (c *Client) setVisible(units map[string]*events.Unit) {
+	c.visible = make(map[string]bool, len(units))
+	for id := range units {
+		if id != c.unitID {
+			c.visible[id] = true
+		}
+	}
 }
 
 // readPump pumps messages from the websocket connection to the hub.
@@ -57,11 +109,11 @@ type Client struct {
 // The application runs readPump in a per-connection goroutine. The application
 // ensures that there is at most one reader on a connection by executing all
 // reads from this goroutine.
-func (c *Client) readPump(world *w.World, unitID string) {
+func (c *Client) readPump(world *w.World) {
 	defer func() {
 		c.hub.unregister <- c
 		c.conn.Close()
-		removeDisconnectedUnit(c.hub, world, unitID)
+		c.leave(world)
 	}()
 	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -75,16 +127,51 @@ func (c *Client) readPump(world *w.World, unitID string) {
 			break
 		}
 
-		c.hub.broadcast <- message
-
-		var e events.Event
-		err = proto.Unmarshal(message, &e)
+		msgs, err := events.SplitFrame(message)
 		if err != nil {
-			logger.Error("can't unmarshal event",
-				zap.String("message", string(message)),
+			logger.Error("can't split frame",
+				zap.Binary("message", message),
 				zap.Error(err))
+			continue
+		}
+
+		for _, msg := range msgs {
+			var e events.Event
+			err = proto.Unmarshal(msg, &e)
+			if err != nil {
+				logger.Error("can't unmarshal event",
+					zap.Binary("message", msg),
+					zap.Error(err))
+				continue
+			}
+
+			if err = validateEvent(c, &e); err != nil {
+				logger.Info("rejected event",
+					zap.String("unitId", c.unitID),
+					zap.String("type", e.Type.String()),
+					zap.Error(err))
+				continue
+			}
+
+			if e.Type == events.Event_ACK {
+				c.snapshots.ack(e.GetAck().Tick)
+				continue
+			}
+
+			if e.Type == events.Event_ATTACK {
+				attack(c.hub, c.sessions, world, &e)
+				continue
+			}
+
+			world.HandleEvent(&e)
+
+			msg, err = proto.Marshal(&e)
+			if err != nil {
+				logger.Error("can't marshal event", zap.Error(err))
+				continue
+			}
+			c.hub.broadcast <- &outbound{unitID: c.unitID, data: msg}
 		}
-		world.HandleEvent(&e)
 	}
 }
 
@@ -93,12 +180,13 @@ func (c *Client) readPump(world *w.World, unitID string) {
 // A goroutine running writePump is started for each connection. The
 // application ensures that there is at most one writer to a connection by
 // executing all writes from this goroutine.
-func (c *Client) writePump(world *w.World, unitID string) {
+func (c *Client) writePump(world *w.World) {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
-		removeDisconnectedUnit(c.hub, world, unitID)
+		c.leave(world)
+		c.hub.pumps.Done()
 	}()
 	for {
 		select {
@@ -106,7 +194,11 @@ func (c *Client) writePump(world *w.World, unitID string) {
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				// The hub closed the channel.
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				closeMsg := c.closeMsg
+				if closeMsg == nil {
+					closeMsg = []byte{}
+				}
+				c.conn.WriteMessage(websocket.CloseMessage, closeMsg)
 				return
 			}
 
@@ -114,13 +206,14 @@ func (c *Client) writePump(world *w.World, unitID string) {
 			if err != nil {
 				return
 			}
-			w.Write(message)
+			frame := events.AppendFrame(nil, message)
 
-			// Add queued chat messages to the current websocket message.
+			// Add queued events to the current websocket message.
 			n := len(c.send)
 			for i := 0; i < n; i++ {
-				w.Write(<-c.send)
+				frame = events.AppendFrame(frame, <-c.send)
 			}
+			w.Write(frame)
 
 			if err := w.Close(); err != nil {
 				return
@@ -134,23 +227,32 @@ func (c *Client) writePump(world *w.World, unitID string) {
 	}
 }
 
-// serveWs handles websocket requests from the peer.
-func ServeWs(hub *Hub, world *w.World, w http.ResponseWriter, r *http.Request) {
+// serveWs handles websocket requests from the authenticated player. A player
+// whose unit is still in the world gets it back, others get their saved unit
+// or a new one.
+func ServeWs(hub *Hub, sessions *sessions, world *w.World, playerID string, w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		logger.Error("can't upgrade connection", zap.Error(err))
 		return
 	}
-	client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256)}
+
+	client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256), unitID: playerID, sessions: sessions}
+
+	player, resumed := sessions.join(client)
+	client.setVisible(sendToPlayerWorldUnits(world, conn, player, resumed))
+
+	hub.pumps.Add(1)
 	hub.register <- client
 
-	player := sendToNewPlayerWorldUnits(world, conn)
-	sendAllNewUnitConnected(hub, world, player)
+	if !resumed {
+		sendAllNewUnitConnected(hub, world, player)
+	}
 
 	// Allow collection of memory referenced by the caller by doing all work in
 	// new goroutines.
-	go client.writePump(world, player.ID)
-	go client.readPump(world, player.ID)
+	go client.writePump(world)
+	go client.readPump(world)
 }
 
 func sendAllNewUnitConnected(hub *Hub, world *w.World, player *events.Unit) {
@@ -158,35 +260,38 @@ func sendAllNewUnitConnected(hub *Hub, world *w.World, player *events.Unit) {
 		Type: events.Event_CONNECT,
 		Data: &events.Event_Connect{
 			Connect: &events.EventConnect{
-				Unit: world.Units[player.ID],
+				Unit: player,
 			},
 		},
 	}
 
 	msg, _ := proto.Marshal(event)
 
-	hub.broadcast <- msg
+	hub.broadcast <- &outbound{unitID: player.ID, data: msg}
 }
 
-func sendToNewPlayerWorldUnits(world *w.World, conn *websocket.Conn) *events.Unit {
-	player := world.AddPlayer()
+// sendToPlayerWorldUnits sends the player the units it can see, and returns
+// them.
+func sendToPlayerWorldUnits(world *w.World, conn *websocket.Conn, player *events.Unit, resumed bool) map[string]*events.Unit {
+	units := visibleUnits(player.ID, world.Units())
 
 	event := &events.Event{
 		Type: events.Event_INIT,
 		Data: &events.Event_Init{
 			Init: &events.EventInit{
 				PlayerID: player.ID,
-				Units:    world.Units,
+				Units:    units,
 			},
 		},
 	}
-	logger.Info("New player added",
+	logger.Info("Player joined",
 		zap.String("player", player.ID),
-		zap.Int("units", len(world.Units)))
+		zap.Bool("resumed", resumed),
+		zap.Int("units", len(units)))
 
-	msg, _ := proto.Marshal(event)
+	msg, _ := events.MarshalFrame(event)
 	conn.WriteMessage(websocket.BinaryMessage, msg)
-	return player
+	return units
 }
 
 func removeDisconnectedUnit(hub *Hub, world *w.World, unitID string) {
@@ -203,7 +308,8 @@ func removeDisconnectedUnit(hub *Hub, world *w.World, unitID string) {
 		},
 	}
 
+	// Removed first, so no snapshot taken after the event brings the unit back.
+	world.RemoveUnit(unitID)
 	msg, _ := proto.Marshal(event)
-	hub.broadcast <- msg
-	delete(world.Units, unitID)
+	hub.broadcast <- &outbound{unitID: unitID, data: msg}
 }
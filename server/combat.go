@@ -0,0 +1,85 @@
+package main
+
+import (
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+	w "github.com/patrick-me/game_one/world"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// attack resolves the attack of a player or a monster, then tells the
+// clients around about it and the damage and deaths it caused. Attacks during
+// the cooldown are dropped.
+func attack(hub *Hub, sessions *sessions, world *w.World, e *events.Event) {
+	attackerID := e.GetAttack().UnitID
+	direction, hits, ok := world.Attack(attackerID)
+	if !ok {
+		return
+	}
+	e.GetAttack().Direction = direction
+	broadcastEvent(hub, attackerID, e)
+
+	for _, hit := range hits {
+		broadcastEvent(hub, hit.UnitID, &events.Event{
+			Type: events.Event_DAMAGE,
+			Data: &events.Event_Damage{
+				Damage: &events.EventDamage{
+					UnitID:     hit.UnitID,
+					AttackerID: attackerID,
+					Amount:     hit.Damage,
+					Hp:         hit.HP,
+				},
+			},
+		})
+		if hit.HP > 0 {
+			continue
+		}
+
+		logger.Info("unit killed",
+			zap.String("unitId", hit.UnitID),
+			zap.String("killerId", attackerID))
+		sessions.recordKill(attackerID, hit.UnitID)
+		broadcastEvent(hub, hit.UnitID, &events.Event{
+			Type: events.Event_DEATH,
+			Data: &events.Event_Death{
+				Death: &events.EventDeath{
+					UnitID:    hit.UnitID,
+					KillerID:  attackerID,
+					RespawnIn: w.RespawnDelay.Seconds(),
+				},
+			},
+		})
+
+		id := hit.UnitID
+		time.AfterFunc(w.RespawnDelay, func() {
+			respawn(hub, world, id)
+		})
+	}
+}
+
+// respawn brings the dead unit back at a spawn point, or at its home for a
+// monster, unless it has left the world meanwhile.
+func respawn(hub *Hub, world *w.World, unitID string) {
+	unit, ok := world.Respawn(unitID)
+	if !ok {
+		return
+	}
+	broadcastEvent(hub, unitID, &events.Event{
+		Type: events.Event_RESPAWN,
+		Data: &events.Event_Respawn{
+			Respawn: &events.EventRespawn{Unit: unit},
+		},
+	})
+}
+
+// broadcastEvent sends the event to the clients seeing the unit.
+func broadcastEvent(hub *Hub, unitID string, event *events.Event) {
+	msg, err := proto.Marshal(event)
+	if err != nil {
+		logger.Error("can't marshal event", zap.Error(err))
+		return
+	}
+	hub.broadcast <- &outbound{unitID: unitID, data: msg}
+}
@@ -4,6 +4,25 @@
 
 package main
 
+import (
+	"context"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	events "github.com/patrick-me/game_one/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// outbound is an event queued for the clients.
+type outbound struct {
+	// ID of the unit the event is about. Only clients whose unit can see it
+	// receive the event. Empty for events every client receives.
+	unitID string
+
+	data []byte
+}
+
 // Hub maintains the set of active clients and broadcasts messages to the
 // clients.
 type Hub struct {
@@ -11,20 +30,34 @@ type Hub struct {
 	clients map[*Client]bool
 
 	// Inbound messages from the clients.
-	broadcast chan []byte
+	broadcast chan *outbound
+
+	// World snapshots to be delta encoded for every client.
+	snapshot chan *worldSnapshot
 
 	// Register requests from the clients.
 	register chan *Client
 
 	// Unregister requests from clients.
 	unregister chan *Client
+
+	// Shutdown requests with the reason given to the clients.
+	shutdown chan string
+
+	// Close message sent to the clients once the hub is shut down.
+	closing []byte
+
+	// Running write pumps, which flush the queued events before closing.
+	pumps sync.WaitGroup
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		broadcast:  make(chan []byte),
+		broadcast:  make(chan *outbound),
+		snapshot:   make(chan *worldSnapshot),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		shutdown:   make(chan string),
 		clients:    make(map[*Client]bool),
 	}
 }
@@ -33,6 +66,11 @@ func (h *Hub) run() {
 	for {
 		select {
 		case client := <-h.register:
+			if h.closing != nil {
+				client.closeMsg = h.closing
+				close(client.send)
+				continue
+			}
 			h.clients[client] = true
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
@@ -41,13 +79,123 @@ func (h *Hub) run() {
 			}
 		case message := <-h.broadcast:
 			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(h.clients, client)
+				if message.unitID == "" || client.sees(message.unitID) {
+					h.send(client, message.data)
 				}
 			}
+		case snapshot := <-h.snapshot:
+			for client := range h.clients {
+				h.sendSnapshot(client, snapshot)
+			}
+		case reason := <-h.shutdown:
+			h.closeAll(reason)
+		}
+	}
+}
+
+// Shutdown tells every client the server is going away and closes their
+// connections. It waits until the write pumps have flushed their queues or the
+// context is done.
+func (h *Hub) Shutdown(ctx context.Context, reason string) error {
+	h.shutdown <- reason
+
+	flushed := make(chan struct{})
+	go func() {
+		h.pumps.Wait()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeAll queues the shutdown event for every client and closes their send
+// channels, so the write pumps close the connections once the queued events
+// are written. Clients registering later are closed right away.
+func (h *Hub) closeAll(reason string) {
+	h.closing = websocket.FormatCloseMessage(websocket.CloseGoingAway, reason)
+
+	data, err := proto.Marshal(&events.Event{
+		Type: events.Event_SHUTDOWN,
+		Data: &events.Event_Shutdown{
+			Shutdown: &events.EventShutdown{Reason: reason},
+		},
+	})
+	if err != nil {
+		logger.Error("can't marshal event", zap.Error(err))
+	}
+
+	for client := range h.clients {
+		if !h.send(client, data) {
+			continue
+		}
+		client.closeMsg = h.closing
+		close(client.send)
+		delete(h.clients, client)
+	}
+}
+
+// sendSnapshot sends the client the part of the snapshot its unit can see,
+// preceded by the units entering and leaving its view.
+func (h *Hub) sendSnapshot(client *Client, snapshot *worldSnapshot) {
+	visible := visibleUnits(client.unitID, snapshot.units)
+
+	var evs []*events.Event
+	for id, unit := range visible {
+		if id != client.unitID && !client.visible[id] {
+			evs = append(evs, &events.Event{
+				Type: events.Event_ENTER_VIEW,
+				Data: &events.Event_EnterView{
+					EnterView: &events.EventEnterView{Unit: unit},
+				},
+			})
 		}
 	}
+	for id := range client.visible {
+		_, inView := visible[id]
+		_, exists := snapshot.units[id]
+		// Units gone from the world were already announced as disconnected.
+		if !inView && exists {
+			evs = append(evs, &events.Event{
+				Type: events.Event_LEAVE_VIEW,
+				Data: &events.Event_LeaveView{
+					LeaveView: &events.EventLeaveView{UnitID: id},
+				},
+			})
+		}
+	}
+
+	client.setVisible(visible)
+
+	evs = append(evs, client.snapshots.next(&worldSnapshot{
+		tick:  snapshot.tick,
+		units: visible,
+	}))
+	for _, event := range evs {
+		data, err := proto.Marshal(event)
+		if err != nil {
+			logger.Error("can't marshal event", zap.Error(err))
+			continue
+		}
+		if !h.send(client, data) {
+			return
+		}
+	}
+}
+
+// send queues the data for the client, dropping the client when it can't keep
+// up. It reports whether the client is still registered.
+func (h *Hub) send(client *Client, data []byte) bool {
+	select {
+	case client.send <- data:
+		return true
+	default:
+		close(client.send)
+		delete(h.clients, client)
+		return false
+	}
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"math"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+const (
+	// Size in pixels of a cell of the interest grid.
+	cellSize = 160
+
+	// How many cells around its own a client can see.
+	viewCells = 1
+)
+
+// cell is a coordinate in the interest grid.
+type cell struct {
+	x, y int
+}
+
+func cellOf(unit *events.Unit) cell {
+	return cell{
+		x: int(math.Floor(unit.X / cellSize)),
+		y: int(math.Floor(unit.Y / cellSize)),
+	}
+}
+
+// canSee reports whether a unit standing at viewer can see the other unit.
+func canSee(viewer, other *events.Unit) bool {
+	a, b := cellOf(viewer), cellOf(other)
+	return abs(a.x-b.x) <= viewCells && abs(a.y-b.y) <= viewCells
+}
+
+// visibleUnits returns the units visible from the unit with the given ID,
+// including the unit itself.
+func visibleUnits(unitID string, units map[string]*events.Unit) map[string]*events.Unit {
+	visible := make(map[string]*events.Unit)
+	viewer, ok := units[unitID]
+	if !ok {
+		return visible
+	}
+	for id, unit := range units {
+		if canSee(viewer, unit) {
+			visible[id] = unit
+		}
+	}
+	return visible
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -1,15 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/store"
+	"github.com/patrick-me/game_one/tilemap"
 	w "github.com/patrick-me/game_one/world"
 	"go.uber.org/zap"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 )
 
+const (
+	// How often a snapshot of the authoritative unit state is sent to the clients.
+	statePeriod = time.Second / 10
+
+	// Time the clients have to receive their last events on shutdown.
+	shutdownTimeout = 5 * time.Second
+
+	shutdownReason = "server is shutting down"
+)
+
 var logger *zap.Logger
 
 func init() {
@@ -24,27 +42,98 @@ func init() {
 func main() {
 	defer logger.Sync()
 
-	world := &w.World{
-		IsServer: true,
-		Units:    make(map[string]*events.Unit),
+	tiles, err := tilemap.Load(tilemap.Path())
+	if err != nil {
+		logger.Fatal("can't load map", zap.Error(err))
+	}
+
+	world := w.New(true)
+	world.SetMap(tiles)
+	horde := spawnMonsters(world, tiles)
+
+	stopped, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	auth, err := newAuthenticator(os.Getenv("AUTH_SECRET"))
+	if err != nil {
+		logger.Fatal("can't create authenticator", zap.Error(err))
+	}
+	if os.Getenv("AUTH_SECRET") == "" {
+		logger.Warn("AUTH_SECRET is not set, tokens won't survive a restart")
+	}
+
+	profiles, err := store.NewFileStore(store.Path())
+	if err != nil {
+		logger.Fatal("can't open player store", zap.Error(err))
 	}
 
 	hub := NewHub()
+	sessions := newSessions(world, profiles)
 	go hub.run()
+	go world.Run(ctx)
 
 	ws := gin.New()
-	ws.GET("/ws", wsHandler(hub, world))
+	ws.POST("/login", loginHandler(auth))
+	ws.GET("/ws", wsHandler(hub, sessions, auth, world))
 
 	ticker := time.NewTicker(time.Hour * 1)
 	done := make(chan bool)
 
+	stateTicker := time.NewTicker(statePeriod)
+	stateDone := make(chan bool)
+
+	saveTicker := time.NewTicker(savePeriod)
+	saveDone := make(chan bool)
+
+	monsterTicker := time.NewTicker(thinkPeriod)
+	monsterDone := make(chan bool)
+
 	go worldInfo(done, ticker, world)
+	go worldState(stateDone, stateTicker, hub, world)
+	go saveProfiles(saveDone, saveTicker, sessions)
+	go runMonsters(monsterDone, monsterTicker, hub, sessions, world, horde)
 
-	logger.Info("Listening on port: ", zap.String("port", os.Getenv("SERVER_PORT")))
-	ws.Run(":" + os.Getenv("SERVER_PORT"))
+	srv := &http.Server{
+		Addr:    ":" + os.Getenv("SERVER_PORT"),
+		Handler: ws,
+	}
+	go func() {
+		logger.Info("Listening on port: ", zap.String("port", os.Getenv("SERVER_PORT")))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("can't listen", zap.Error(err))
+		}
+	}()
+
+	<-stopped.Done()
+	stop()
+	logger.Info("shutting down", zap.Duration("timeout", shutdownTimeout))
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	// Stop accepting connections first, websockets are hijacked and left to the hub.
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("can't stop listening", zap.Error(err))
+	}
 
 	ticker.Stop()
+	stateTicker.Stop()
+	saveTicker.Stop()
+	monsterTicker.Stop()
 	done <- true
+	stateDone <- true
+	saveDone <- true
+	monsterDone <- true
+
+	if err := hub.Shutdown(shutdownCtx, shutdownReason); err != nil {
+		logger.Error("clients not closed in time", zap.Error(err))
+	}
+	sessions.saveAll()
+	cancel()
+	logger.Info("server stopped")
 }
 
 func worldInfo(done chan bool, ticker *time.Ticker, world *w.World) {
@@ -53,21 +142,106 @@ func worldInfo(done chan bool, ticker *time.Ticker, world *w.World) {
 		case <-done:
 			return
 		case <-ticker.C:
-			logger.Info("units in the world", zap.Int("units", len(world.Units)))
+			logger.Info("units in the world", zap.Int("units", world.Len()))
+		}
+	}
+}
+
+// worldState sends the state of the units to the clients, stamped with the
+// world tick. A stalled world has nothing new to send.
+func worldState(done chan bool, ticker *time.Ticker, hub *Hub, world *w.World) {
+	var last uint64
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			tick := world.Tick()
+			if tick == last {
+				continue
+			}
+			last = tick
+			hub.snapshot <- &worldSnapshot{
+				tick:  tick,
+				units: world.Units(),
+			}
+		}
+	}
+}
+
+// loginRequest may carry a token issued earlier, to log in as the same player
+// again. The token may have expired, for no longer than renewalGrace.
+// Otherwise a new player is created.
+type loginRequest struct {
+	Token string `json:"token"`
+}
+
+type loginResponse struct {
+	PlayerID string `json:"playerID"`
+	Token    string `json:"token"`
+}
+
+// loginHandler issues player tokens to the clients knowing the game's
+// AUTH_TOKEN.
+func loginHandler(auth *authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != os.Getenv("AUTH_TOKEN") {
+			logger.Info("Login without the game token")
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		var req loginRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
+		}
+
+		playerID := uuid.New().String()
+		if req.Token != "" {
+			if id, err := auth.renewable(req.Token); err == nil {
+				playerID = id
+			} else {
+				logger.Info("Token not renewed, creating a new player", zap.Error(err))
+			}
+		}
+
+		token, err := auth.issue(playerID)
+		if err != nil {
+			logger.Error("can't issue token", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		logger.Info("Player logged in", zap.String("player", playerID))
+		c.JSON(http.StatusOK, loginResponse{PlayerID: playerID, Token: token})
+	}
+}
+
+func saveProfiles(done chan bool, ticker *time.Ticker, sessions *sessions) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			sessions.saveAll()
 		}
 	}
 }
 
-func wsHandler(hub *Hub, world *w.World) gin.HandlerFunc {
+func wsHandler(hub *Hub, sessions *sessions, auth *authenticator, world *w.World) gin.HandlerFunc {
 	return func(hub *Hub, world *w.World) gin.HandlerFunc {
 		return func(c *gin.Context) {
-			auth := c.Request.Header.Get("Authorization")
+			token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
 
-			if auth != os.Getenv("AUTH_TOKEN") {
-				logger.Info("Request without authorization", zap.String("auth", auth))
+			playerID, err := auth.verify(token)
+			if err != nil {
+				logger.Info("Unauthorized request", zap.Error(err))
+				c.AbortWithStatus(http.StatusUnauthorized)
 				return
 			}
-			ServeWs(hub, world, c.Writer, c.Request)
+			ServeWs(hub, sessions, world, playerID, c.Writer, c.Request)
 		}
 	}(hub, world)
 }
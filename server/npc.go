@@ -0,0 +1,68 @@
+package main
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/patrick-me/game_one/npc"
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
+	w "github.com/patrick-me/game_one/world"
+	"go.uber.org/zap"
+)
+
+// How often the monsters decide what to do.
+const thinkPeriod = time.Second / 10
+
+// spawnMonsters puts the monsters placed on the map in the world.
+func spawnMonsters(world *w.World, tiles *tilemap.Map) *npc.Horde {
+	horde := npc.NewHorde()
+	for _, spawn := range tiles.MonsterSpawns() {
+		kind, ok := w.MonsterKinds[spawn.Kind]
+		if !ok {
+			logger.Warn("unknown monster kind", zap.String("kind", spawn.Kind))
+			continue
+		}
+		unit := world.AddMonster("monster-"+uuid.New().String(), kind, spawn.X, spawn.Y)
+		horde.Add(npc.New(unit, kind, world))
+	}
+	logger.Info("monsters spawned", zap.Int("monsters", horde.Len()))
+	return horde
+}
+
+// runMonsters makes the monsters think, their events go through the world
+// and the hub like those of the players.
+func runMonsters(done chan bool, ticker *time.Ticker, hub *Hub, sessions *sessions, world *w.World, horde *npc.Horde) {
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			for _, event := range horde.Think(world.Units(), now) {
+				handleMonsterEvent(hub, sessions, world, event)
+			}
+		}
+	}
+}
+
+func handleMonsterEvent(hub *Hub, sessions *sessions, world *w.World, event *events.Event) {
+	switch event.Type {
+	case events.Event_ATTACK:
+		// Unlike players, monsters turn to their target to attack it.
+		e := event.GetAttack()
+		world.UpdateUnit(e.UnitID, func(unit *events.Unit) {
+			if unit.Action != events.Action_DEAD {
+				unit.Direction = e.Direction
+			}
+		})
+		attack(hub, sessions, world, event)
+
+	case events.Event_MOVE:
+		world.HandleEvent(event)
+		broadcastEvent(hub, event.GetMove().UnitID, event)
+
+	case events.Event_IDLE:
+		world.HandleEvent(event)
+		broadcastEvent(hub, event.GetIdle().UnitID, event)
+	}
+}
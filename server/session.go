@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/store"
+	w "github.com/patrick-me/game_one/world"
+	"go.uber.org/zap"
+)
+
+const (
+	// How long the unit of a disconnected player stays in the world, waiting
+	// for the player to come back.
+	sessionGracePeriod = 30 * time.Second
+
+	// How often the profiles of the players in the world are saved.
+	savePeriod = time.Minute
+)
+
+// session ties a unit to the client controlling it across reconnections.
+type session struct {
+	// Connected client, nil during the grace period.
+	client *Client
+
+	// Removes the unit at the end of the grace period.
+	expiry *time.Timer
+
+	// Profile of the player, updated from the unit when saved.
+	profile *store.Profile
+
+	// Start of the play time not counted in the profile yet, zero while the
+	// player is away.
+	since time.Time
+}
+
+// sessions tracks the players having a unit in the world, by player ID, and
+// keeps their profiles in the store.
+type sessions struct {
+	mu       sync.Mutex
+	players  map[string]*session
+	world    *w.World
+	profiles store.PlayerStore
+}
+
+func newSessions(world *w.World, profiles store.PlayerStore) *sessions {
+	return &sessions{
+		players:  make(map[string]*session),
+		world:    world,
+		profiles: profiles,
+	}
+}
+
+// join attaches the client to the session of its player and returns the unit
+// of the player. It reports whether the player already had a unit in the
+// world, which the client resumes. A client still attached to the session is
+// disconnected, its connection is most likely dead but not timed out yet.
+// Other players get their saved unit back, or a new one.
+func (s *sessions) join(client *Client) (*events.Unit, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := client.unitID
+	if sess, ok := s.players[id]; ok {
+		if unit, ok := s.world.SetAway(id, false); ok {
+			if sess.expiry != nil {
+				sess.expiry.Stop()
+				sess.expiry = nil
+			}
+			if sess.client != nil {
+				sess.client.conn.Close()
+			}
+			sess.client = client
+			if sess.since.IsZero() {
+				sess.since = time.Now()
+			}
+			return unit, true
+		}
+	}
+
+	var unit *events.Unit
+	profile, err := s.profiles.Load(id)
+	if err == nil {
+		unit = s.world.RestorePlayer(&events.Unit{
+			ID:         id,
+			SpriteName: profile.SpriteName,
+			Speed:      profile.Speed,
+			X:          profile.X,
+			Y:          profile.Y,
+		})
+	} else {
+		if !errors.Is(err, store.ErrNotFound) {
+			logger.Error("can't load profile, starting over", zap.String("player", id), zap.Error(err))
+		}
+		unit = s.world.AddPlayer(id)
+		profile = &store.Profile{ID: id}
+	}
+	profile.Stats.Sessions++
+
+	s.players[id] = &session{client: client, profile: profile, since: time.Now()}
+	return unit, false
+}
+
+// detach saves the profile of the client's player, makes its unit away and
+// starts the grace period of its session, expire is called unless the player
+// comes back in time. It reports false when another client has resumed the
+// session meanwhile.
+func (s *sessions) detach(client *Client, expire func()) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := client.unitID
+	sess, ok := s.players[id]
+	if !ok || sess.client != client {
+		return false
+	}
+	s.save(id, sess)
+	sess.client = nil
+	sess.since = time.Time{}
+	s.world.SetAway(id, true)
+
+	var expiry *time.Timer
+	expiry = time.AfterFunc(sessionGracePeriod, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// The player may have come back while the timer fired.
+		if sess.expiry != expiry || s.players[id] != sess {
+			return
+		}
+		s.save(id, sess)
+		delete(s.players, id)
+		expire()
+	})
+	sess.expiry = expiry
+	return true
+}
+
+// recordKill counts the kill in the stats of both players.
+func (s *sessions) recordKill(killerID, victimID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sess, ok := s.players[killerID]; ok {
+		sess.profile.Stats.Kills++
+	}
+	if sess, ok := s.players[victimID]; ok {
+		sess.profile.Stats.Deaths++
+	}
+}
+
+// saveAll saves the profiles of every player in the world.
+func (s *sessions) saveAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, sess := range s.players {
+		s.save(id, sess)
+	}
+}
+
+// save copies the state of the player's unit to its profile and stores it.
+func (s *sessions) save(id string, sess *session) {
+	profile := sess.profile
+	if unit, ok := s.world.Unit(id); ok {
+		profile.SpriteName = unit.SpriteName
+		profile.Speed = unit.Speed
+		profile.X, profile.Y = unit.X, unit.Y
+	}
+	if !sess.since.IsZero() {
+		now := time.Now()
+		profile.Stats.PlayTime += now.Sub(sess.since).Seconds()
+		sess.since = now
+	}
+
+	if err := s.profiles.Save(profile); err != nil {
+		logger.Error("can't save profile", zap.String("player", id), zap.Error(err))
+	}
+}
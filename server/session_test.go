@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/patrick-me/game_one/store"
+	w "github.com/patrick-me/game_one/world"
+)
+
+func TestSessionResumeAfterDetach(t *testing.T) {
+	profiles, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	world := w.New(true)
+	s := newSessions(world, profiles)
+
+	first := &Client{unitID: "p"}
+	if _, resumed := s.join(first); resumed {
+		t.Fatal("new player resumed a session")
+	}
+	if !s.detach(first, func() { t.Error("session expired") }) {
+		t.Fatal("client not detached")
+	}
+	if unit, _ := world.Unit("p"); !unit.Invulnerable {
+		t.Error("unit of a detached player is vulnerable")
+	}
+
+	unit, resumed := s.join(&Client{unitID: "p"})
+	if !resumed {
+		t.Fatal("player didn't resume the session")
+	}
+	if unit.Invulnerable {
+		t.Error("resumed unit is still invulnerable")
+	}
+	if s.detach(first, func() {}) {
+		t.Error("old client detached the resumed session")
+	}
+}
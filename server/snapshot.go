@@ -0,0 +1,76 @@
+package main
+
+import (
+	"sync"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+// Number of sent snapshots kept per client as candidate delta baselines.
+const snapshotHistory = 32
+
+// worldSnapshot is the state of the world at a server tick.
+type worldSnapshot struct {
+	tick  uint64
+	units map[string]*events.Unit
+}
+
+// snapshots tracks the snapshots sent to a client and the last one it has
+// acknowledged, which is the baseline for the next delta.
+type snapshots struct {
+	mu      sync.Mutex
+	acked   uint64
+	history map[uint64]map[string]*events.Unit
+}
+
+// next records the snapshot as sent and returns the event carrying its delta
+// against the acknowledged baseline, or a full snapshot when there is none.
+func (s *snapshots) next(snapshot *worldSnapshot) *events.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.history == nil {
+		s.history = make(map[uint64]map[string]*events.Unit)
+	}
+
+	baseTick := s.acked
+	base, ok := s.history[baseTick]
+	if !ok {
+		baseTick = 0
+	}
+	deltas, removed := events.Diff(base, snapshot.units)
+
+	s.history[snapshot.tick] = snapshot.units
+	events.TrimHistory(s.history, snapshotHistory)
+
+	return &events.Event{
+		Type: events.Event_SNAPSHOT,
+		Data: &events.Event_Snapshot{
+			Snapshot: &events.EventSnapshot{
+				Tick:     snapshot.tick,
+				BaseTick: baseTick,
+				Units:    deltas,
+				Removed:  removed,
+			},
+		},
+	}
+}
+
+// ack moves the baseline to the acknowledged snapshot.
+func (s *snapshots) ack(tick uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tick <= s.acked {
+		return
+	}
+	if _, ok := s.history[tick]; !ok {
+		return
+	}
+	s.acked = tick
+	for t := range s.history {
+		if t < tick {
+			delete(s.history, t)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"math"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+var (
+	errForbiddenEvent = errors.New("event type can't be sent by a client")
+	errEmptyEvent     = errors.New("event has no payload")
+	errForeignUnit    = errors.New("event belongs to another unit")
+	errBadDirection   = errors.New("unknown direction")
+	errBadVelocity    = errors.New("velocity is not a finite number")
+)
+
+// validateEvent checks an event received from the client before it reaches the
+// world. Only events the player is allowed to send are accepted, and they are
+// bound to the client's own unit.
+func validateEvent(c *Client, e *events.Event) error {
+	switch e.Type {
+	case events.Event_MOVE:
+		move := e.GetMove()
+		if move == nil {
+			return errEmptyEvent
+		}
+		if _, ok := events.Direction_name[int32(move.Direction)]; !ok {
+			return errBadDirection
+		}
+		if !finite(move.Vx) || !finite(move.Vy) {
+			return errBadVelocity
+		}
+		return bindUnit(c, &move.UnitID)
+
+	case events.Event_IDLE:
+		idle := e.GetIdle()
+		if idle == nil {
+			return errEmptyEvent
+		}
+		return bindUnit(c, &idle.UnitID)
+
+	case events.Event_ATTACK:
+		attack := e.GetAttack()
+		if attack == nil {
+			return errEmptyEvent
+		}
+		return bindUnit(c, &attack.UnitID)
+
+	case events.Event_ACK:
+		if e.GetAck() == nil {
+			return errEmptyEvent
+		}
+		return nil
+
+	default:
+		return errForbiddenEvent
+	}
+}
+
+func finite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// bindUnit fills in the client's unit ID when the event omits it and rejects
+// events addressed to any other unit.
+func bindUnit(c *Client, unitID *string) error {
+	if *unitID != "" && *unitID != c.unitID {
+		return errForeignUnit
+	}
+	*unitID = c.unitID
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+func moveEvent(unitID string, direction events.Direction, vx, vy float64) *events.Event {
+	return &events.Event{
+		Type: events.Event_MOVE,
+		Data: &events.Event_Move{
+			Move: &events.EventMove{UnitID: unitID, Direction: direction, Vx: vx, Vy: vy},
+		},
+	}
+}
+
+func TestValidateEvent(t *testing.T) {
+	idle := &events.Event_Idle{Idle: &events.EventIdle{}}
+
+	tests := []struct {
+		name  string
+		event *events.Event
+		err   error
+	}{
+		{"move", moveEvent("me", events.Direction_LEFT, -1, 0), nil},
+		{"move without unit", moveEvent("", events.Direction_UP, 0, -1), nil},
+		{"idle", &events.Event{Type: events.Event_IDLE, Data: idle}, nil},
+		{"attack", &events.Event{
+			Type: events.Event_ATTACK,
+			Data: &events.Event_Attack{Attack: &events.EventAttack{UnitID: "me"}},
+		}, nil},
+		{"ack", &events.Event{Type: events.Event_ACK, Data: &events.Event_Ack{Ack: &events.EventAck{Tick: 1}}}, nil},
+
+		{"connect", &events.Event{
+			Type: events.Event_CONNECT,
+			Data: &events.Event_Connect{Connect: &events.EventConnect{Unit: &events.Unit{ID: "me"}}},
+		}, errForbiddenEvent},
+		{"disconnect", &events.Event{
+			Type: events.Event_DISCONNECT,
+			Data: &events.Event_Disconnect{Disconnect: &events.EventDisconnect{UnitID: "other"}},
+		}, errForbiddenEvent},
+		{"init", &events.Event{Type: events.Event_INIT, Data: &events.Event_Init{Init: &events.EventInit{}}}, errForbiddenEvent},
+		{"snapshot", &events.Event{Type: events.Event_SNAPSHOT, Data: &events.Event_Snapshot{Snapshot: &events.EventSnapshot{}}}, errForbiddenEvent},
+		{"damage", &events.Event{Type: events.Event_DAMAGE}, errForbiddenEvent},
+		{"death", &events.Event{Type: events.Event_DEATH}, errForbiddenEvent},
+		{"respawn", &events.Event{Type: events.Event_RESPAWN}, errForbiddenEvent},
+		{"shutdown", &events.Event{Type: events.Event_SHUTDOWN}, errForbiddenEvent},
+		{"unknown type", &events.Event{Type: 99}, errForbiddenEvent},
+
+		{"move carrying idle", &events.Event{Type: events.Event_MOVE, Data: idle}, errEmptyEvent},
+		{"idle without payload", &events.Event{Type: events.Event_IDLE}, errEmptyEvent},
+		{"attack carrying idle", &events.Event{Type: events.Event_ATTACK, Data: idle}, errEmptyEvent},
+		{"ack carrying idle", &events.Event{Type: events.Event_ACK, Data: idle}, errEmptyEvent},
+
+		{"foreign move", moveEvent("other", events.Direction_LEFT, -1, 0), errForeignUnit},
+		{"foreign idle", &events.Event{
+			Type: events.Event_IDLE,
+			Data: &events.Event_Idle{Idle: &events.EventIdle{UnitID: "other"}},
+		}, errForeignUnit},
+		{"foreign attack", &events.Event{
+			Type: events.Event_ATTACK,
+			Data: &events.Event_Attack{Attack: &events.EventAttack{UnitID: "other"}},
+		}, errForeignUnit},
+
+		{"unknown direction", moveEvent("me", 42, 1, 0), errBadDirection},
+		{"NaN velocity", moveEvent("me", events.Direction_RIGHT, math.NaN(), 0), errBadVelocity},
+		{"infinite velocity", moveEvent("me", events.Direction_DOWN, 0, math.Inf(1)), errBadVelocity},
+		{"negative infinite velocity", moveEvent("me", events.Direction_LEFT, math.Inf(-1), 0), errBadVelocity},
+	}
+
+	client := &Client{unitID: "me"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateEvent(client, tt.event); !errors.Is(err, tt.err) {
+				t.Errorf("validateEvent = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateEventFillsInUnit(t *testing.T) {
+	client := &Client{unitID: "me"}
+
+	move := moveEvent("", events.Direction_LEFT, -1, 0)
+	if err := validateEvent(client, move); err != nil {
+		t.Fatal(err)
+	}
+	if id := move.GetMove().UnitID; id != "me" {
+		t.Errorf("move unit = %q, want the client's", id)
+	}
+
+	idle := &events.Event{Type: events.Event_IDLE, Data: &events.Event_Idle{Idle: &events.EventIdle{}}}
+	if err := validateEvent(client, idle); err != nil {
+		t.Fatal(err)
+	}
+	if id := idle.GetIdle().UnitID; id != "me" {
+		t.Errorf("idle unit = %q, want the client's", id)
+	}
+
+	attack := &events.Event{Type: events.Event_ATTACK, Data: &events.Event_Attack{Attack: &events.EventAttack{}}}
+	if err := validateEvent(client, attack); err != nil {
+		t.Fatal(err)
+	}
+	if id := attack.GetAttack().UnitID; id != "me" {
+		t.Errorf("attack unit = %q, want the client's", id)
+	}
+}
@@ -0,0 +1,124 @@
+// Package store persists the player profiles between sessions.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultPath is the directory the profiles are kept in.
+const DefaultPath = "data/players"
+
+// Path returns the profile directory set in the PLAYERS_PATH environment
+// variable, or the default one.
+func Path() string {
+	if path := os.Getenv("PLAYERS_PATH"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+var (
+	ErrNotFound  = errors.New("player not found")
+	errInvalidID = errors.New("invalid player ID")
+)
+
+// Profile is what is kept of a player between sessions.
+type Profile struct {
+	ID         string  `json:"id"`
+	SpriteName string  `json:"spriteName"`
+	Speed      float64 `json:"speed"`
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Stats      Stats   `json:"stats"`
+}
+
+// Stats are the player's counters over all the sessions.
+type Stats struct {
+	Sessions int `json:"sessions"`
+
+	// Time spent in the world, in seconds.
+	PlayTime float64 `json:"playTime"`
+
+	Kills  int `json:"kills"`
+	Deaths int `json:"deaths"`
+}
+
+// PlayerStore loads and saves the profiles by player ID.
+type PlayerStore interface {
+	// Load returns ErrNotFound for a player never saved.
+	Load(id string) (*Profile, error)
+	Save(profile *Profile) error
+}
+
+// FileStore keeps every profile in a JSON file named after the player ID.
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore creates the directory of the store if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Load(id string) (*Profile, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	data, err := os.ReadFile(path)
+	s.mu.Unlock()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var profile Profile
+	if err = json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("can't parse profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
+
+// Save replaces the file of the player at once, so a crash never leaves a
+// partly written profile.
+func (s *FileStore) Save(profile *Profile) error {
+	path, err := s.path(profile.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// path returns the file of the player. IDs are never trusted as file names.
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || id == "." || id == ".." {
+		return "", errInvalidID
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
@@ -0,0 +1,289 @@
+// Package tilemap loads tile maps made with the Tiled editor, saved in its
+// JSON format. The server uses the collision layers for walkability and the
+// client renders the tile layers.
+package tilemap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// DefaultPath is the map loaded by the server and the client.
+const DefaultPath = "resources/maps/dungeon.json"
+
+// Path returns the map file set in the MAP_PATH environment variable, or the
+// default map.
+func Path() string {
+	if path := os.Getenv("MAP_PATH"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Tiled stores flip flags in the high bits of a tile GID.
+const flipFlags = 0xE0000000
+
+const (
+	TileLayer   = "tilelayer"
+	ObjectGroup = "objectgroup"
+)
+
+const (
+	// SpawnLayer is the object group holding the spawn points.
+	SpawnLayer = "spawns"
+
+	// MonsterLayer is the object group placing the monsters, every object is
+	// named after the kind of its monster.
+	MonsterLayer = "monsters"
+)
+
+// Map is an orthogonal Tiled map.
+type Map struct {
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	TileWidth  int        `json:"tilewidth"`
+	TileHeight int        `json:"tileheight"`
+	Layers     []*Layer   `json:"layers"`
+	Tilesets   []*Tileset `json:"tilesets"`
+
+	// Directory of the map file, tileset images are relative to it.
+	Dir string `json:"-"`
+
+	solid []bool
+}
+
+// Layer is a tile layer or an object group.
+type Layer struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	Visible    bool       `json:"visible"`
+	Opacity    float64    `json:"opacity"`
+	Data       []uint32   `json:"data"`
+	Objects    []*Object  `json:"objects"`
+	Properties []Property `json:"properties"`
+}
+
+// Object is a point or a rectangle placed in an object group.
+type Object struct {
+	Name   string  `json:"name"`
+	Type   string  `json:"type"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Point is a position on the map, in pixels.
+type Point struct {
+	X, Y float64
+}
+
+// Tileset is a tileset embedded in the map, made of a single image.
+type Tileset struct {
+	FirstGID    uint32 `json:"firstgid"`
+	Name        string `json:"name"`
+	Image       string `json:"image"`
+	ImageWidth  int    `json:"imagewidth"`
+	ImageHeight int    `json:"imageheight"`
+	TileWidth   int    `json:"tilewidth"`
+	TileHeight  int    `json:"tileheight"`
+	Columns     int    `json:"columns"`
+	TileCount   int    `json:"tilecount"`
+	Margin      int    `json:"margin"`
+	Spacing     int    `json:"spacing"`
+	Source      string `json:"source"`
+}
+
+// Property is a custom property set on a layer in Tiled.
+type Property struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// Load reads and validates a map file.
+func Load(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Map
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("can't parse map %s: %w", path, err)
+	}
+	m.Dir = filepath.Dir(path)
+
+	if err = m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid map %s: %w", path, err)
+	}
+	m.buildCollision()
+	return &m, nil
+}
+
+func (m *Map) validate() error {
+	if m.Width <= 0 || m.Height <= 0 || m.TileWidth <= 0 || m.TileHeight <= 0 {
+		return errors.New("map has no size")
+	}
+	for _, ts := range m.Tilesets {
+		if ts.Source != "" {
+			return fmt.Errorf("external tileset %s is not supported, embed it in the map", ts.Source)
+		}
+		if ts.Columns <= 0 || ts.TileWidth <= 0 || ts.TileHeight <= 0 {
+			return fmt.Errorf("tileset %s has no tiles", ts.Name)
+		}
+	}
+	for _, l := range m.Layers {
+		if l.Type == TileLayer && (l.Width != m.Width || len(l.Data) != m.Width*m.Height) {
+			return fmt.Errorf("layer %s has %d tiles, want %d", l.Name, len(l.Data), m.Width*m.Height)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON defaults the layer to visible and opaque when the file omits
+// those fields.
+func (l *Layer) UnmarshalJSON(data []byte) error {
+	type layer Layer
+	v := layer{Visible: true, Opacity: 1}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*l = Layer(v)
+	return nil
+}
+
+// IsCollision reports whether the layer marks blocked tiles, either by its
+// name or by a "collision" bool property.
+func (l *Layer) IsCollision() bool {
+	if l.Type != TileLayer {
+		return false
+	}
+	for _, p := range l.Properties {
+		if p.Name == "collision" {
+			v, _ := p.Value.(bool)
+			return v
+		}
+	}
+	return l.Name == "collision"
+}
+
+// Tile returns the GID of the tile at x, y with the flip flags cleared, or 0
+// for an empty tile.
+func (l *Layer) Tile(x, y int) uint32 {
+	return l.Data[y*l.Width+x] &^ flipFlags
+}
+
+func (m *Map) buildCollision() {
+	m.solid = make([]bool, m.Width*m.Height)
+	for _, l := range m.Layers {
+		if !l.IsCollision() {
+			continue
+		}
+		for i, gid := range l.Data {
+			if gid&^flipFlags != 0 {
+				m.solid[i] = true
+			}
+		}
+	}
+}
+
+// SpawnPoints returns where units stand when they spawn, from the objects of
+// the spawns layer: a point object, or the bottom center of a rectangle.
+func (m *Map) SpawnPoints() []Point {
+	var points []Point
+	for _, l := range m.Layers {
+		if l.Type != ObjectGroup || l.Name != SpawnLayer {
+			continue
+		}
+		for _, o := range l.Objects {
+			points = append(points, Point{X: o.X + o.Width/2, Y: o.Y + o.Height})
+		}
+	}
+	return points
+}
+
+// MonsterSpawn is where a monster of a kind lives.
+type MonsterSpawn struct {
+	Kind string
+	Point
+}
+
+// MonsterSpawns returns the monsters placed in the monsters layer, standing
+// like units at spawn points.
+func (m *Map) MonsterSpawns() []MonsterSpawn {
+	var spawns []MonsterSpawn
+	for _, l := range m.Layers {
+		if l.Type != ObjectGroup || l.Name != MonsterLayer {
+			continue
+		}
+		for _, o := range l.Objects {
+			spawns = append(spawns, MonsterSpawn{
+				Kind:  o.Name,
+				Point: Point{X: o.X + o.Width/2, Y: o.Y + o.Height},
+			})
+		}
+	}
+	return spawns
+}
+
+// PixelWidth returns the width of the map in pixels.
+func (m *Map) PixelWidth() float64 {
+	return float64(m.Width * m.TileWidth)
+}
+
+// PixelHeight returns the height of the map in pixels.
+func (m *Map) PixelHeight() float64 {
+	return float64(m.Height * m.TileHeight)
+}
+
+// Walkable reports whether the tile at x, y is inside the map and not blocked.
+func (m *Map) Walkable(x, y int) bool {
+	if x < 0 || y < 0 || x >= m.Width || y >= m.Height {
+		return false
+	}
+	return !m.solid[y*m.Width+x]
+}
+
+// Blocked reports whether the rectangle in pixels overlaps any tile that is
+// not walkable.
+func (m *Map) Blocked(x, y, w, h float64) bool {
+	x0 := int(math.Floor(x / float64(m.TileWidth)))
+	y0 := int(math.Floor(y / float64(m.TileHeight)))
+	x1 := int(math.Ceil((x+w)/float64(m.TileWidth))) - 1
+	y1 := int(math.Ceil((y+h)/float64(m.TileHeight))) - 1
+	for ty := y0; ty <= y1; ty++ {
+		for tx := x0; tx <= x1; tx++ {
+			if !m.Walkable(tx, ty) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Tileset returns the tileset holding the tile GID.
+func (m *Map) Tileset(gid uint32) (*Tileset, bool) {
+	var found *Tileset
+	for _, ts := range m.Tilesets {
+		if ts.FirstGID <= gid && (found == nil || ts.FirstGID > found.FirstGID) {
+			found = ts
+		}
+	}
+	return found, found != nil
+}
+
+// TileRect returns the position of the tile GID in the tileset image.
+func (ts *Tileset) TileRect(gid uint32) (x, y, w, h int) {
+	index := int(gid - ts.FirstGID)
+	col, row := index%ts.Columns, index/ts.Columns
+	x = ts.Margin + col*(ts.TileWidth+ts.Spacing)
+	y = ts.Margin + row*(ts.TileHeight+ts.Spacing)
+	return x, y, ts.TileWidth, ts.TileHeight
+}
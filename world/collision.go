@@ -0,0 +1,132 @@
+package world
+
+import (
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
+)
+
+const (
+	// Size of the unit sprites in pixels.
+	SpriteWidth  = 16
+	SpriteHeight = 28
+
+	// Default size of the world in pixels.
+	DefaultWidth  = 320
+	DefaultHeight = 320
+)
+
+// Rect is an axis-aligned rectangle in world coordinates.
+type Rect struct {
+	X, Y, W, H float64
+}
+
+// Intersects reports whether the rectangles overlap.
+func (r Rect) Intersects(o Rect) bool {
+	return r.X < o.X+o.W && o.X < r.X+r.W &&
+		r.Y < o.Y+o.H && o.Y < r.Y+r.H
+}
+
+// Center returns the middle of the rectangle.
+func (r Rect) Center() (float64, float64) {
+	return r.X + r.W/2, r.Y + r.H/2
+}
+
+// Contains reports whether o lies entirely inside r.
+func (r Rect) Contains(o Rect) bool {
+	return o.X >= r.X && o.X+o.W <= r.X+r.W &&
+		o.Y >= r.Y && o.Y+o.H <= r.Y+r.H
+}
+
+// Hitbox returns the collision box of a unit: the lower part of its sprite,
+// where the feet are, so units can pass in front of each other's heads.
+func Hitbox(unit *events.Unit) Rect {
+	return hitboxAt(unit.X, unit.Y)
+}
+
+func hitboxAt(x, y float64) Rect {
+	return Rect{
+		X: x + 2,
+		Y: y + SpriteHeight/2,
+		W: SpriteWidth - 4,
+		H: SpriteHeight / 2,
+	}
+}
+
+// blocked reports whether a unit can't stand at x, y. Other units only block
+// if the unit doesn't overlap them already, so units spawned on top of each
+// other can walk apart.
+func (w *World) blocked(unit *events.Unit, x, y float64) bool {
+	box := hitboxAt(x, y)
+	if w.solid(box) {
+		return true
+	}
+
+	current := Hitbox(unit)
+	for id, other := range w.units {
+		// Units walk over the dead.
+		if id == unit.ID || other.Action == events.Action_DEAD {
+			continue
+		}
+		otherBox := Hitbox(other)
+		if box.Intersects(otherBox) && !current.Intersects(otherBox) {
+			return true
+		}
+	}
+	return false
+}
+
+// solid reports whether the box leaves the world or overlaps the map walls.
+func (w *World) solid(box Rect) bool {
+	if !w.bounds.Contains(box) {
+		return true
+	}
+	return w.tiles != nil && w.tiles.Blocked(box.X, box.Y, box.W, box.H)
+}
+
+// free reports whether a unit can be placed at x, y without overlapping
+// anything.
+func (w *World) free(x, y float64) bool {
+	box := hitboxAt(x, y)
+	if w.solid(box) {
+		return false
+	}
+	for _, other := range w.units {
+		if box.Intersects(Hitbox(other)) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindPath returns the positions a unit at fromX, fromY walks through to get
+// to toX, toY around the walls of the map. The last one is the destination,
+// or the middle of its tile when the unit doesn't fit there. It reports false
+// when there is no way, or no map to find it on.
+func (w *World) FindPath(fromX, fromY, toX, toY float64) ([]tilemap.Point, bool) {
+	w.mu.RLock()
+	paths := w.paths
+	w.mu.RUnlock()
+	if paths == nil {
+		return nil, false
+	}
+
+	// Paths are found for the hitbox, from its center.
+	offsetX, offsetY := hitboxAt(0, 0).Center()
+	points, ok := paths.Find(fromX+offsetX, fromY+offsetY, toX+offsetX, toY+offsetY)
+	for i := range points {
+		points[i].X -= offsetX
+		points[i].Y -= offsetY
+	}
+	return points, ok
+}
+
+// moveBy moves the unit by dx, dy one axis at a time, so a unit blocked on one
+// axis still slides along the other.
+func (w *World) moveBy(unit *events.Unit, dx, dy float64) {
+	if dx != 0 && !w.blocked(unit, unit.X+dx, unit.Y) {
+		unit.X += dx
+	}
+	if dy != 0 && !w.blocked(unit, unit.X, unit.Y+dy) {
+		unit.Y += dy
+	}
+}
@@ -0,0 +1,93 @@
+package world
+
+import (
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+const (
+	// Health of a unit when it spawns.
+	MaxHP = 100
+
+	// Health taken by a hit.
+	AttackDamage = 20
+
+	// Reach of a melee attack beyond the attacker's hitbox, in pixels.
+	AttackRange = 12
+
+	// Shortest time between two attacks of a unit.
+	AttackCooldown = 500 * time.Millisecond
+)
+
+// Hit is a unit damaged by an attack.
+type Hit struct {
+	UnitID string
+	Damage int32
+
+	// Health left, zero when the hit killed the unit.
+	HP int32
+}
+
+// AttackBox returns the area a unit hits, in front of its hitbox in the
+// direction it faces.
+func AttackBox(unit *events.Unit) Rect {
+	box := Hitbox(unit)
+	switch unit.Direction {
+	case events.Direction_LEFT:
+		return Rect{X: box.X - AttackRange, Y: box.Y, W: AttackRange, H: box.H}
+	case events.Direction_UP:
+		return Rect{X: box.X, Y: box.Y - AttackRange, W: box.W, H: AttackRange}
+	case events.Direction_DOWN:
+		return Rect{X: box.X, Y: box.Y + box.H, W: box.W, H: AttackRange}
+	}
+	return Rect{X: box.X + box.W, Y: box.Y, W: AttackRange, H: box.H}
+}
+
+// Attack resolves a melee attack of the unit against every living unit in
+// its attack box, monsters sparing each other, and returns the direction the
+// unit attacked in. It reports false when the unit can't attack, because it
+// is dead or its last attack is too recent.
+func (w *World) Attack(id string) (direction events.Direction, hits []Hit, ok bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	attacker, ok := w.units[id]
+	if !ok || attacker.Action == events.Action_DEAD {
+		return 0, nil, false
+	}
+	cooldown := uint64(AttackCooldown / TickDuration)
+	if last, ok := w.attacks[id]; ok && w.tick < last+cooldown {
+		return 0, nil, false
+	}
+	w.attacks[id] = w.tick
+	w.unprotect(attacker)
+
+	_, monster := w.homes[id]
+	area := AttackBox(attacker)
+	for otherID, other := range w.units {
+		if otherID == id || other.Action == events.Action_DEAD || other.Invulnerable {
+			continue
+		}
+		if _, ally := w.homes[otherID]; monster && ally {
+			continue
+		}
+		if !area.Intersects(Hitbox(other)) {
+			continue
+		}
+		damage := min(int32(AttackDamage), other.Hp)
+		other.Hp -= damage
+		if other.Hp == 0 {
+			kill(other)
+		}
+		hits = append(hits, Hit{UnitID: otherID, Damage: damage, HP: other.Hp})
+	}
+	return attacker.Direction, hits, true
+}
+
+// kill stops the unit, a dead unit neither moves nor attacks.
+func kill(unit *events.Unit) {
+	unit.Hp = 0
+	unit.Action = events.Action_DEAD
+	unit.Vx, unit.Vy = 0, 0
+}
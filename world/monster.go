@@ -0,0 +1,66 @@
+package world
+
+import (
+	"math/rand"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
+)
+
+// MonsterKind is a type of monster. Its name is also the name of its sprites.
+type MonsterKind struct {
+	Name  string
+	HP    int32
+	Speed float64
+
+	// Distance at which the monster notices a player, in pixels.
+	AggroRadius float64
+
+	// Share of its health under which the monster runs away, zero for a
+	// monster fighting to death.
+	FleeBelow float64
+
+	// Time the monster waits between two attacks, no shorter than the
+	// AttackCooldown of every unit.
+	AttackPeriod time.Duration
+}
+
+// MonsterKinds are the monsters a map can place, by name.
+var MonsterKinds = map[string]MonsterKind{
+	"goblin": {
+		Name: "goblin", HP: 40, Speed: 90,
+		AggroRadius: 96, FleeBelow: 0.3, AttackPeriod: 800 * time.Millisecond,
+	},
+	"skelet": {
+		Name: "skelet", HP: 60, Speed: 60,
+		AggroRadius: 128, AttackPeriod: 1200 * time.Millisecond,
+	},
+	"orc_warrior": {
+		Name: "orc_warrior", HP: 120, Speed: 70,
+		AggroRadius: 112, FleeBelow: 0.15, AttackPeriod: 1500 * time.Millisecond,
+	},
+}
+
+// AddMonster puts a monster of the kind in the world, its feet at x, y or
+// next to it when the spot is taken. The monster respawns there when killed.
+func (w *World) AddMonster(id string, kind MonsterKind, x, y float64) *events.Unit {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	unit := &events.Unit{
+		ID:         id,
+		Action:     events.Action_IDLE,
+		Frame:      int32(rnd.Intn(4)),
+		SpriteName: kind.Name,
+		Speed:      kind.Speed,
+		Hp:         kind.HP,
+		MaxHp:      kind.HP,
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.homes[id] = tilemap.Point{X: x - SpriteWidth/2, Y: y - SpriteHeight}
+	w.spawn(unit, rnd)
+	w.units[id] = unit
+	return cloneUnit(unit)
+}
@@ -0,0 +1,60 @@
+package world
+
+import (
+	"math"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+const (
+	// Length of a simulation step. Unit speeds are in pixels per second and
+	// don't depend on it.
+	TickDuration = time.Second / 60
+
+	// Most steps run at once to catch up with a late ticker.
+	maxCatchUpSteps = 10
+)
+
+// Velocity returns the unit vector pointing in the direction.
+func Velocity(direction events.Direction) (vx, vy float64) {
+	switch direction {
+	case events.Direction_LEFT:
+		return -1, 0
+	case events.Direction_RIGHT:
+		return 1, 0
+	case events.Direction_UP:
+		return 0, -1
+	case events.Direction_DOWN:
+		return 0, 1
+	}
+	return 0, 0
+}
+
+// Normalize scales the vector down to a length of at most 1, so moving
+// diagonally is not faster than moving straight. Shorter vectors, e.g. a
+// half-tilted analog stick, are kept as they are.
+func Normalize(vx, vy float64) (float64, float64) {
+	length := math.Hypot(vx, vy)
+	if length <= 1 {
+		return vx, vy
+	}
+	return vx / length, vy / length
+}
+
+// Facing returns the direction a unit moving along the vector faces. Sprites
+// only face left or right, so the horizontal component wins. A unit that
+// doesn't move keeps its current direction.
+func Facing(vx, vy float64, current events.Direction) events.Direction {
+	switch {
+	case vx < 0:
+		return events.Direction_LEFT
+	case vx > 0:
+		return events.Direction_RIGHT
+	case vy < 0:
+		return events.Direction_UP
+	case vy > 0:
+		return events.Direction_DOWN
+	}
+	return current
+}
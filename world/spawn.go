@@ -0,0 +1,170 @@
+package world
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+)
+
+const (
+	// Time a dead unit waits before it respawns.
+	RespawnDelay = 5 * time.Second
+
+	// Time a unit can't be hurt after spawning, unless it attacks.
+	SpawnProtection = 2 * time.Second
+
+	// Units closer than this to a spawn point make it crowded.
+	spawnCrowdRadius = 64
+)
+
+// Places tried around a taken spawn point, nearest first.
+var spawnOffsets = [][2]float64{
+	{0, 0},
+	{-SpriteWidth, 0}, {SpriteWidth, 0}, {0, -SpriteWidth}, {0, SpriteWidth},
+	{-SpriteWidth, -SpriteWidth}, {SpriteWidth, -SpriteWidth}, {-SpriteWidth, SpriteWidth}, {SpriteWidth, SpriteWidth},
+	{-2 * SpriteWidth, 0}, {2 * SpriteWidth, 0}, {0, -2 * SpriteWidth}, {0, 2 * SpriteWidth},
+}
+
+// ChooseSpawn returns the position of a unit standing at the least crowded
+// spawn point of the map, or next to it when the point itself is taken. It
+// reports false when the map has no spawn point or all of them are blocked.
+func (w *World) ChooseSpawn() (x, y float64, ok bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.chooseSpawn(rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func (w *World) chooseSpawn(rnd *rand.Rand) (x, y float64, ok bool) {
+	best := -1
+	ties := 0
+	for _, p := range w.spawns {
+		// Spawn points are where the feet of the unit stand.
+		px, py, ok := w.freeNear(p.X-SpriteWidth/2, p.Y-SpriteHeight)
+		if !ok {
+			continue
+		}
+
+		crowd := 0
+		for _, unit := range w.units {
+			if unit.Action == events.Action_DEAD {
+				continue
+			}
+			if math.Hypot(unit.X-px, unit.Y-py) < spawnCrowdRadius {
+				crowd++
+			}
+		}
+
+		switch {
+		case best < 0 || crowd < best:
+			best, ties = crowd, 1
+			x, y = px, py
+		case crowd == best:
+			// Pick one of the equally crowded points at random.
+			ties++
+			if rnd.Intn(ties) == 0 {
+				x, y = px, py
+			}
+		}
+	}
+	return x, y, best >= 0
+}
+
+// freeNear returns the first free place around x, y.
+func (w *World) freeNear(x, y float64) (float64, float64, bool) {
+	for _, offset := range spawnOffsets {
+		if w.free(x+offset[0], y+offset[1]) {
+			return x + offset[0], y + offset[1], true
+		}
+	}
+	return 0, 0, false
+}
+
+// spawn moves the unit to its home for a monster, or a spawn point, or
+// anywhere free when neither is usable, and protects it for a while.
+func (w *World) spawn(unit *events.Unit, rnd *rand.Rand) {
+	var x, y float64
+	var ok bool
+	if home, isMonster := w.homes[unit.ID]; isMonster {
+		x, y, ok = w.freeNear(home.X, home.Y)
+	} else {
+		x, y, ok = w.chooseSpawn(rnd)
+	}
+	if ok {
+		unit.X, unit.Y = x, y
+	} else {
+		w.place(unit, rnd)
+	}
+	w.protect(unit)
+}
+
+// protect makes the unit invulnerable for SpawnProtection, or for as long as
+// its player is away.
+func (w *World) protect(unit *events.Unit) {
+	unit.Invulnerable = true
+	if !w.away[unit.ID] {
+		w.protected[unit.ID] = w.tick + uint64(SpawnProtection/TickDuration)
+	}
+}
+
+// unprotect ends the spawn protection of the unit.
+func (w *World) unprotect(unit *events.Unit) {
+	unit.Invulnerable = false
+	delete(w.protected, unit.ID)
+}
+
+// SetAway stops the unit of a player who left and makes it invulnerable, so
+// neither monsters nor players kill it while the player reconnects, or makes
+// it vulnerable again when the player is back. It returns a copy of the unit,
+// and reports false when the unit is gone.
+func (w *World) SetAway(id string, away bool) (*events.Unit, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	unit, ok := w.units[id]
+	if !ok {
+		return nil, false
+	}
+	delete(w.protected, id)
+	unit.Invulnerable = away
+	if away {
+		w.away[id] = true
+		if unit.Action != events.Action_DEAD {
+			unit.Action = events.Action_IDLE
+			unit.Vx, unit.Vy = 0, 0
+		}
+	} else {
+		delete(w.away, id)
+	}
+	return cloneUnit(unit), true
+}
+
+// Respawn brings the dead unit back to life at a spawn point, or at its home
+// for a monster. It reports false when the unit is gone or alive.
+func (w *World) Respawn(id string) (*events.Unit, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	unit, ok := w.units[id]
+	if !ok || unit.Action != events.Action_DEAD {
+		return nil, false
+	}
+	unit.Hp = unit.MaxHp
+	unit.Action = events.Action_IDLE
+	w.spawn(unit, rand.New(rand.NewSource(time.Now().UnixNano())))
+	return cloneUnit(unit), true
+}
+
+// expireProtections ends the spawn protections that have run out.
+func (w *World) expireProtections() {
+	for id, until := range w.protected {
+		if w.tick < until {
+			continue
+		}
+		if unit, ok := w.units[id]; ok {
+			unit.Invulnerable = false
+		}
+		delete(w.protected, id)
+	}
+}
@@ -1,96 +1,376 @@
 package world
 
 import (
-	"github.com/google/uuid"
+	"context"
+	"github.com/patrick-me/game_one/pathfind"
 	_ "github.com/patrick-me/game_one/proto"
 	events "github.com/patrick-me/game_one/proto"
-	"log"
+	"github.com/patrick-me/game_one/tilemap"
+	"google.golang.org/protobuf/proto"
 	"math/rand"
+	"sync"
 	"time"
 )
 
+// World holds the units of the game. It is safe for concurrent use: every
+// mutation goes through its methods under a single lock, and readers get
+// copies of the units instead of the shared pointers.
 type World struct {
-	MyID     string
 	IsServer bool
-	Units    map[string]*events.Unit
+
+	mu     sync.RWMutex
+	myID   string
+	units  map[string]*events.Unit
+	bounds Rect
+	tiles  *tilemap.Map
+	paths  *pathfind.Finder
+	spawns []tilemap.Point
+	tick   uint64
+
+	// Tick of the last attack of every unit.
+	attacks map[string]uint64
+
+	// Tick the spawn protection of every protected unit ends at.
+	protected map[string]uint64
+
+	// Place every monster spawns at, instead of the spawn points.
+	homes map[string]tilemap.Point
+
+	// Units of the players who left, invulnerable until they come back.
+	away map[string]bool
+}
+
+func New(isServer bool) *World {
+	return &World{
+		IsServer:  isServer,
+		units:     make(map[string]*events.Unit),
+		attacks:   make(map[string]uint64),
+		protected: make(map[string]uint64),
+		homes:     make(map[string]tilemap.Point),
+		away:      make(map[string]bool),
+		bounds:    Rect{W: DefaultWidth, H: DefaultHeight},
+	}
+}
+
+// SetMap makes the map the walkable area of the world.
+func (w *World) SetMap(m *tilemap.Map) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tiles = m
+	box := hitboxAt(0, 0)
+	w.paths = pathfind.ForMap(m, box.W, box.H)
+	w.bounds = Rect{W: m.PixelWidth(), H: m.PixelHeight()}
+	w.spawns = m.SpawnPoints()
+}
+
+// Bounds returns the area units can walk in.
+func (w *World) Bounds() Rect {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.bounds
+}
+
+// MyID returns the ID of the unit controlled by this client.
+func (w *World) MyID() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.myID
+}
+
+// Unit returns a copy of the unit with the given ID.
+func (w *World) Unit(id string) (*events.Unit, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	unit, ok := w.units[id]
+	if !ok {
+		return nil, false
+	}
+	return cloneUnit(unit), true
+}
+
+// Units returns a snapshot of all units in the world.
+func (w *World) Units() map[string]*events.Unit {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return cloneUnits(w.units)
+}
+
+// Len returns the number of units in the world.
+func (w *World) Len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.units)
 }
 
 func (w *World) HandleEvent(e *events.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	switch e.Type {
 	case events.Event_CONNECT:
 		event := e.GetConnect()
-		w.Units[event.Unit.ID] = event.Unit
+		w.units[event.Unit.ID] = cloneUnit(event.Unit)
 
 	case events.Event_INIT:
 		event := e.GetInit()
 		if !w.IsServer {
-			w.MyID = event.PlayerID
-			w.Units = event.Units
+			w.myID = event.PlayerID
+			w.units = cloneUnits(event.Units)
 		}
 
 	case events.Event_MOVE:
 		event := e.GetMove()
-		unit := w.Units[event.UnitID]
+		unit, ok := w.units[event.UnitID]
+		if !ok || unit.Action == events.Action_DEAD {
+			return
+		}
+		vx, vy := event.Vx, event.Vy
+		if vx == 0 && vy == 0 {
+			vx, vy = Velocity(event.Direction)
+		}
 		unit.Action = events.Action_RUN
-		unit.Direction = event.Direction
+		unit.Vx, unit.Vy = Normalize(vx, vy)
+		unit.Direction = Facing(unit.Vx, unit.Vy, unit.Direction)
+		unit.LastInputSeq = event.Seq
 
 	case events.Event_IDLE:
 		event := e.GetIdle()
-
-		unit := w.Units[event.UnitID]
+		unit, ok := w.units[event.UnitID]
+		if !ok || unit.Action == events.Action_DEAD {
+			return
+		}
 		unit.Action = events.Action_IDLE
+		unit.Vx, unit.Vy = 0, 0
+		unit.LastInputSeq = event.Seq
 
 	case events.Event_DISCONNECT:
 		event := e.GetDisconnect()
-		delete(w.Units, event.UnitID)
+		delete(w.units, event.UnitID)
+
+	case events.Event_ENTER_VIEW:
+		event := e.GetEnterView()
+		w.units[event.Unit.ID] = cloneUnit(event.Unit)
+
+	case events.Event_LEAVE_VIEW:
+		event := e.GetLeaveView()
+		delete(w.units, event.UnitID)
+
+	case events.Event_ATTACK:
+		event := e.GetAttack()
+		if unit, ok := w.units[event.UnitID]; ok && !w.IsServer {
+			unit.Direction = event.Direction
+		}
+
+	case events.Event_DAMAGE:
+		event := e.GetDamage()
+		if unit, ok := w.units[event.UnitID]; ok && !w.IsServer {
+			unit.Hp = event.Hp
+		}
+
+	case events.Event_DEATH:
+		event := e.GetDeath()
+		if unit, ok := w.units[event.UnitID]; ok && !w.IsServer {
+			kill(unit)
+		}
+
+	case events.Event_RESPAWN:
+		event := e.GetRespawn()
+		w.units[event.Unit.ID] = cloneUnit(event.Unit)
 	}
 
 }
 
-func (w *World) AddPlayer() *events.Unit {
+// ApplyState overwrites the units with the authoritative state received from
+// the server. Remote units missing from the state are gone or out of view, and
+// are removed.
+func (w *World) ApplyState(units map[string]*events.Unit) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for id := range w.units {
+		if _, ok := units[id]; !ok && id != w.myID {
+			w.removeUnit(id)
+		}
+	}
+	for id, state := range units {
+		unit, ok := w.units[id]
+		if !ok {
+			w.units[id] = cloneUnit(state)
+			continue
+		}
+		unit.X = state.X
+		unit.Y = state.Y
+		unit.Action = state.Action
+		unit.Direction = state.Direction
+		unit.Speed = state.Speed
+		unit.LastInputSeq = state.LastInputSeq
+		unit.Vx = state.Vx
+		unit.Vy = state.Vy
+		unit.Hp = state.Hp
+		unit.MaxHp = state.MaxHp
+		unit.Invulnerable = state.Invulnerable
+	}
+}
+
+// AddPlayer spawns a unit for the player with the given ID.
+func (w *World) AddPlayer(id string) *events.Unit {
 
 	skins := []string{
 		"elf_f", "elf_m", "knight_f", "knight_m", "lizard_f", "lizard_m", "wizzard_f", "wizzard_m",
 	}
-	id := uuid.New().String()
 	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
 	unit := &events.Unit{
 		ID:         id,
 		Action:     events.Action_IDLE,
-		X:          rnd.Float64() * 320,
-		Y:          rnd.Float64() * 320,
 		Frame:      int32(rnd.Intn(4)),
 		SpriteName: skins[rnd.Intn(len(skins))],
-		Speed:      float64(rnd.Intn(4) + 1),
+		Speed:      float64(60 * (rnd.Intn(4) + 1)),
+		Hp:         MaxHP,
+		MaxHp:      MaxHP,
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.spawn(unit, rnd)
+	w.units[id] = unit
+	return cloneUnit(unit)
+
+}
+
+// RestorePlayer spawns a unit saved in a previous session. It goes back to its
+// position, unless the spot is taken now and it goes to a spawn point.
+func (w *World) RestorePlayer(saved *events.Unit) *events.Unit {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	unit := cloneUnit(saved)
+	unit.Action = events.Action_IDLE
+	unit.Frame = int32(rnd.Intn(4))
+	unit.Vx, unit.Vy = 0, 0
+	unit.Hp, unit.MaxHp = MaxHP, MaxHP
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.free(unit.X, unit.Y) {
+		w.protect(unit)
+	} else {
+		w.spawn(unit, rnd)
+	}
+	w.units[unit.ID] = unit
+	return cloneUnit(unit)
+}
+
+// place moves the unit to a random free spot, giving up after a while on a
+// crowded map.
+func (w *World) place(unit *events.Unit, rnd *rand.Rand) {
+	for i := 0; i < 100; i++ {
+		unit.X = w.bounds.X + rnd.Float64()*(w.bounds.W-SpriteWidth)
+		unit.Y = w.bounds.Y + rnd.Float64()*(w.bounds.H-SpriteHeight)
+		if w.free(unit.X, unit.Y) {
+			return
+		}
+	}
+}
+
+// UpdateUnit runs fn on the unit with the given ID while holding the world
+// lock. It reports whether the unit exists.
+func (w *World) UpdateUnit(id string, fn func(unit *events.Unit)) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	unit, ok := w.units[id]
+	if ok {
+		fn(unit)
 	}
+	return ok
+}
 
-	w.Units[id] = unit
-	return unit
+// RemoveUnit deletes the unit with the given ID from the world.
+func (w *World) RemoveUnit(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.removeUnit(id)
+}
 
+func (w *World) removeUnit(id string) {
+	delete(w.units, id)
+	delete(w.attacks, id)
+	delete(w.protected, id)
+	delete(w.homes, id)
+	delete(w.away, id)
 }
 
-func (w *World) Evolve() {
-	ticker := time.NewTicker(time.Second / 60)
+// Run advances the simulation in fixed steps of TickDuration until the
+// context is cancelled. The time actually elapsed is measured and accumulated,
+// so a late ticker is caught up with extra steps instead of slowing the units
+// down.
+func (w *World) Run(ctx context.Context) error {
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+	last := time.Now()
+	var lag time.Duration
 
 	for {
 		select {
-		case <-ticker.C:
-			for _, unit := range w.Units {
-				if unit.Action == events.Action_RUN {
-					switch unit.Direction {
-					case events.Direction_LEFT:
-						unit.X -= unit.Speed
-					case events.Direction_RIGHT:
-						unit.X += unit.Speed
-					case events.Direction_UP:
-						unit.Y -= unit.Speed
-					case events.Direction_DOWN:
-						unit.Y += unit.Speed
-					default:
-						log.Println("UNKNOWN DIRECTION: ", unit.Direction)
-					}
-				}
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			lag += now.Sub(last)
+			last = now
+
+			steps := 0
+			for lag >= TickDuration && steps < maxCatchUpSteps {
+				w.Step(TickDuration)
+				lag -= TickDuration
+				steps++
+			}
+			// Too far behind, e.g. after the process was suspended: drop the
+			// backlog instead of fast-forwarding the units.
+			if steps == maxCatchUpSteps {
+				lag = 0
 			}
 		}
 	}
 }
+
+// Step advances the simulation by dt and counts a tick. Run calls it with
+// TickDuration, tests and custom loops may drive the world with it directly.
+func (w *World) Step(dt time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, unit := range w.units {
+		w.MoveUnit(unit, dt.Seconds())
+	}
+	w.tick++
+	w.expireProtections()
+}
+
+// Tick returns the number of steps simulated so far.
+func (w *World) Tick() uint64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.tick
+}
+
+// MoveUnit advances a running unit by dt seconds, stopping it at walls,
+// other units and the world bounds. The server and the client prediction share
+// it so that both move units by the same rules. The world must be locked by
+// the caller, which is the case in Step and UpdateUnit callbacks.
+func (w *World) MoveUnit(unit *events.Unit, dt float64) {
+	if unit.Action != events.Action_RUN {
+		return
+	}
+	w.moveBy(unit, unit.Vx*unit.Speed*dt, unit.Vy*unit.Speed*dt)
+}
+
+func cloneUnit(unit *events.Unit) *events.Unit {
+	return proto.Clone(unit).(*events.Unit)
+}
+
+func cloneUnits(units map[string]*events.Unit) map[string]*events.Unit {
+	clone := make(map[string]*events.Unit, len(units))
+	for id, unit := range units {
+		clone[id] = cloneUnit(unit)
+	}
+	return clone
+}
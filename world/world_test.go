@@ -0,0 +1,229 @@
+package world
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	events "github.com/patrick-me/game_one/proto"
+	"github.com/patrick-me/game_one/tilemap"
+)
+
+func loadMap(t testing.TB) *tilemap.Map {
+	t.Helper()
+	m, err := tilemap.Load("../" + tilemap.DefaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func moveEvent(id string, vx, vy float64) *events.Event {
+	return &events.Event{
+		Type: events.Event_MOVE,
+		Data: &events.Event_Move{
+			Move: &events.EventMove{UnitID: id, Vx: vx, Vy: vy},
+		},
+	}
+}
+
+// TestConcurrentAccess drives the world from several goroutines at once, run
+// it with -race.
+func TestConcurrentAccess(t *testing.T) {
+	world := New(true)
+	world.SetMap(loadMap(t))
+
+	var ids []string
+	for i := 0; i < 8; i++ {
+		id := fmt.Sprintf("player-%d", i)
+		world.AddPlayer(id)
+		ids = append(ids, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	go world.Run(ctx)
+
+	var wg sync.WaitGroup
+	worker := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ctx.Err() == nil; i++ {
+				fn(i)
+			}
+		}()
+	}
+
+	worker(func(i int) {
+		world.HandleEvent(moveEvent(ids[i%len(ids)], float64(i%3-1), float64(i%5-2)))
+	})
+	worker(func(i int) {
+		for _, unit := range world.Units() {
+			unit.X++ // Copies are the caller's own.
+		}
+	})
+	worker(func(i int) {
+		world.Attack(ids[i%len(ids)])
+	})
+	worker(func(i int) {
+		world.UpdateUnit(ids[i%len(ids)], func(unit *events.Unit) {
+			if i%7 == 0 {
+				kill(unit)
+			}
+		})
+		world.Respawn(ids[i%len(ids)])
+	})
+	worker(func(i int) {
+		if unit, ok := world.Unit(ids[i%len(ids)]); ok {
+			world.FindPath(unit.X, unit.Y, 100, 100)
+		}
+	})
+	wg.Wait()
+
+	if world.Len() != len(ids) {
+		t.Errorf("world has %d units, want %d", world.Len(), len(ids))
+	}
+}
+
+func TestUnitsReturnsCopies(t *testing.T) {
+	world := New(true)
+	unit := world.AddPlayer("p")
+
+	units := world.Units()
+	units["p"].X += 100
+	unit.X += 100
+
+	got, _ := world.Unit("p")
+	if got.X == units["p"].X || got.X == unit.X {
+		t.Errorf("changing a copy changed the world unit to x=%v", got.X)
+	}
+}
+
+func TestStepMovesUnitsDeterministically(t *testing.T) {
+	world := New(true)
+	world.HandleEvent(&events.Event{
+		Type: events.Event_CONNECT,
+		Data: &events.Event_Connect{
+			Connect: &events.EventConnect{
+				Unit: &events.Unit{ID: "u", X: 10, Y: 20, Speed: 60, Action: events.Action_IDLE},
+			},
+		},
+	})
+	world.HandleEvent(moveEvent("u", 1, 0))
+
+	// 60 pixels per second for 30 ticks of about 1/60 s.
+	const steps = 30
+	for i := 0; i < steps; i++ {
+		world.Step(TickDuration)
+	}
+
+	unit, _ := world.Unit("u")
+	wantX := 10 + 60*steps*TickDuration.Seconds()
+	if math.Abs(unit.X-wantX) > 1e-9 || unit.Y != 20 {
+		t.Errorf("unit at %v, %v after %d steps, want %v, 20", unit.X, unit.Y, steps, wantX)
+	}
+	if world.Tick() != steps {
+		t.Errorf("tick = %d, want %d", world.Tick(), steps)
+	}
+}
+
+func TestMonstersSpareEachOther(t *testing.T) {
+	world := New(true)
+	world.AddMonster("m1", MonsterKinds["goblin"], 100, 100)
+	world.AddMonster("m2", MonsterKinds["goblin"], 110, 100)
+	world.HandleEvent(&events.Event{
+		Type: events.Event_CONNECT,
+		Data: &events.Event_Connect{
+			Connect: &events.EventConnect{
+				Unit: &events.Unit{ID: "p", Speed: 60, Hp: MaxHP, MaxHp: MaxHP, Action: events.Action_IDLE},
+			},
+		},
+	})
+	m1, _ := world.Unit("m1")
+	for _, id := range []string{"m2", "p"} {
+		world.UpdateUnit(id, func(unit *events.Unit) {
+			unit.X, unit.Y = m1.X+10, m1.Y
+		})
+	}
+	world.UpdateUnit("m1", func(unit *events.Unit) {
+		unit.Direction = events.Direction_RIGHT
+	})
+	for i := 0; i < int(SpawnProtection/TickDuration)+1; i++ {
+		world.Step(TickDuration)
+	}
+
+	_, hits, ok := world.Attack("m1")
+	if !ok {
+		t.Fatal("m1 couldn't attack")
+	}
+	if len(hits) != 1 || hits[0].UnitID != "p" {
+		t.Errorf("m1 hit %+v, want only p", hits)
+	}
+}
+
+func TestAwayUnitsCantBeHurt(t *testing.T) {
+	world := New(true)
+	for _, id := range []string{"p", "q"} {
+		world.HandleEvent(&events.Event{
+			Type: events.Event_CONNECT,
+			Data: &events.Event_Connect{
+				Connect: &events.EventConnect{
+					Unit: &events.Unit{ID: id, X: 100, Y: 100, Speed: 60, Hp: MaxHP, MaxHp: MaxHP, Action: events.Action_IDLE},
+				},
+			},
+		})
+	}
+	world.UpdateUnit("p", func(unit *events.Unit) {
+		unit.X, unit.Direction = 90, events.Direction_RIGHT
+	})
+	world.HandleEvent(moveEvent("q", 1, 0))
+
+	unit, ok := world.SetAway("q", true)
+	if !ok || !unit.Invulnerable || unit.Action != events.Action_IDLE {
+		t.Fatalf("away unit %+v, want it idle and invulnerable", unit)
+	}
+	if _, hits, _ := world.Attack("p"); len(hits) != 0 {
+		t.Errorf("p hit %+v while q is away", hits)
+	}
+
+	world.SetAway("q", false)
+	for i := 0; i < int(AttackCooldown/TickDuration)+1; i++ {
+		world.Step(TickDuration)
+	}
+	if _, hits, _ := world.Attack("p"); len(hits) != 1 || hits[0].UnitID != "q" {
+		t.Errorf("p hit %+v once q is back, want q", hits)
+	}
+}
+
+func TestApplyStateRemovesMissingUnits(t *testing.T) {
+	world := New(false)
+	world.HandleEvent(&events.Event{
+		Type: events.Event_INIT,
+		Data: &events.Event_Init{
+			Init: &events.EventInit{
+				PlayerID: "me",
+				Units: map[string]*events.Unit{
+					"me":    {ID: "me"},
+					"ghost": {ID: "ghost"},
+				},
+			},
+		},
+	})
+
+	world.ApplyState(map[string]*events.Unit{"other": {ID: "other", X: 5}})
+
+	units := world.Units()
+	if _, ok := units["ghost"]; ok {
+		t.Error("unit missing from the state is still in the world")
+	}
+	if _, ok := units["me"]; !ok {
+		t.Error("local unit was removed")
+	}
+	if other, ok := units["other"]; !ok || other.X != 5 {
+		t.Errorf("other = %+v, want it added at x=5", other)
+	}
+}